@@ -0,0 +1,112 @@
+// Package affine implements 2D affine transformation matrices.
+package affine
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Identity matrix, it leaves points unchanged.
+var Identity = Matrix{A: 1, D: 1}
+
+// Matrix is a 2D affine transformation
+//
+//	| A C E |
+//	| B D F |
+//	| 0 0 1 |
+//
+// The field order matches the SVG matrix(a b c d e f) transform.
+// The zero value is not the identity, start from Identity.
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Translation returns a translation matrix
+func Translation(x, y float64) Matrix {
+	return Matrix{A: 1, D: 1, E: x, F: y}
+}
+
+// Rotation returns a rotation matrix about the origin, angle in radians
+func Rotation(angle float64) Matrix {
+	sin, cos := math.Sincos(angle)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// Scaling returns a scaling matrix about the origin
+func Scaling(x, y float64) Matrix {
+	return Matrix{A: x, D: y}
+}
+
+// Shearing returns a skew matrix, angles in radians
+func Shearing(angleX, angleY float64) Matrix {
+	return Matrix{A: 1, B: math.Tan(angleY), C: math.Tan(angleX), D: 1}
+}
+
+// Mul returns m × n. The result applies n first, then m.
+func (m Matrix) Mul(n Matrix) Matrix {
+	return Matrix{
+		A: m.A*n.A + m.C*n.B,
+		B: m.B*n.A + m.D*n.B,
+		C: m.A*n.C + m.C*n.D,
+		D: m.B*n.C + m.D*n.D,
+		E: m.A*n.E + m.C*n.F + m.E,
+		F: m.B*n.E + m.D*n.F + m.F,
+	}
+}
+
+// Translate returns m with a translation applied before it, in the local coordinate system of m
+func (m Matrix) Translate(x, y float64) Matrix {
+	return m.Mul(Translation(x, y))
+}
+
+// Rotate returns m with a rotation applied before it, in the local coordinate system of m
+func (m Matrix) Rotate(angle float64) Matrix {
+	return m.Mul(Rotation(angle))
+}
+
+// Scale returns m with a scaling applied before it, in the local coordinate system of m
+func (m Matrix) Scale(x, y float64) Matrix {
+	return m.Mul(Scaling(x, y))
+}
+
+// Skew returns m with a skew applied before it, in the local coordinate system of m
+func (m Matrix) Skew(angleX, angleY float64) Matrix {
+	return m.Mul(Shearing(angleX, angleY))
+}
+
+// Det returns the determinant
+func (m Matrix) Det() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// Invert returns the inverse matrix. A singular matrix returns the zero Matrix.
+func (m Matrix) Invert() Matrix {
+	det := m.Det()
+	if det == 0 {
+		return Matrix{}
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}
+}
+
+// IsIdentity reports whether m is the identity matrix
+func (m Matrix) IsIdentity() bool {
+	return m == Identity
+}
+
+// Apply transforms point
+func (m Matrix) Apply(pt v.Vec) v.Vec {
+	return v.Vec{X: m.A*pt.X + m.C*pt.Y + m.E, Y: m.B*pt.X + m.D*pt.Y + m.F}
+}
+
+// ApplyVector transforms a direction vector, translation is ignored
+func (m Matrix) ApplyVector(vec v.Vec) v.Vec {
+	return v.Vec{X: m.A*vec.X + m.C*vec.Y, Y: m.B*vec.X + m.D*vec.Y}
+}
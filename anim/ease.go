@@ -0,0 +1,196 @@
+package anim
+
+import "math"
+
+// Ease maps linear progress t in the range 0-1 to eased progress.
+// Ease(0) is 0 and Ease(1) is 1, values in between may overshoot.
+type Ease func(t float64) float64
+
+// Linear is the identity easing
+func Linear(t float64) float64 { return t }
+
+// Penner easing functions. In eases start slow, Out eases end slow and InOut eases do both.
+var (
+	InQuad    Ease = func(t float64) float64 { return t * t }
+	OutQuad        = Out(InQuad)
+	InOutQuad      = InOut(InQuad)
+
+	InCubic    Ease = func(t float64) float64 { return t * t * t }
+	OutCubic        = Out(InCubic)
+	InOutCubic      = InOut(InCubic)
+
+	InQuart    Ease = func(t float64) float64 { return t * t * t * t }
+	OutQuart        = Out(InQuart)
+	InOutQuart      = InOut(InQuart)
+
+	InQuint    Ease = func(t float64) float64 { return t * t * t * t * t }
+	OutQuint        = Out(InQuint)
+	InOutQuint      = InOut(InQuint)
+
+	InSine    Ease = func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+	OutSine        = Out(InSine)
+	InOutSine      = InOut(InSine)
+
+	InExpo Ease = func(t float64) float64 {
+		if t <= 0 {
+			return 0
+		}
+		return math.Pow(2, 10*t-10)
+	}
+	OutExpo   = Out(InExpo)
+	InOutExpo = InOut(InExpo)
+
+	InCirc    Ease = func(t float64) float64 { return 1 - math.Sqrt(1-t*t) }
+	OutCirc        = Out(InCirc)
+	InOutCirc      = InOut(InCirc)
+
+	// InBack pulls back before moving forward
+	InBack Ease = func(t float64) float64 {
+		const s = 1.70158
+		return t * t * ((s+1)*t - s)
+	}
+	OutBack   = Out(InBack)
+	InOutBack = InOut(InBack)
+
+	// InElastic oscillates with growing amplitude
+	InElastic Ease = func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+		return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*(2*math.Pi)/3)
+	}
+	OutElastic   = Out(InElastic)
+	InOutElastic = InOut(InElastic)
+
+	// OutBounce bounces at the end like a dropped ball
+	OutBounce Ease = func(t float64) float64 {
+		const n, d = 7.5625, 2.75
+		switch {
+		case t < 1/d:
+			return n * t * t
+		case t < 2/d:
+			t -= 1.5 / d
+			return n*t*t + 0.75
+		case t < 2.5/d:
+			t -= 2.25 / d
+			return n*t*t + 0.9375
+		}
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+	InBounce    = Out(OutBounce)
+	InOutBounce = InOut(InBounce)
+)
+
+// Out returns the reverse of the ease, so an In ease becomes an Out ease
+func Out(ease Ease) Ease {
+	return func(t float64) float64 { return 1 - ease(1-t) }
+}
+
+// InOut returns an ease that runs ease in the first half and its reverse in the second half
+func InOut(ease Ease) Ease {
+	return func(t float64) float64 {
+		if t < 0.5 {
+			return ease(2*t) / 2
+		}
+		return 1 - ease(2-2*t)/2
+	}
+}
+
+// CubicBezier returns a timing curve like the CSS cubic-bezier() function.
+// The curve starts at (0, 0), ends at (1, 1) and x1 and x2 must be in the range 0-1.
+//
+//	ease := anim.CubicBezier(0.25, 0.1, 0.25, 1) // CSS "ease"
+func CubicBezier(x1, y1, x2, y2 float64) Ease {
+	bezier := func(a, b, s float64) float64 {
+		ms := 1 - s
+		return 3*ms*ms*s*a + 3*ms*s*s*b + s*s*s
+	}
+	return func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+		// Find the curve parameter where x equals t, x is monotonic for x1, x2 in 0-1
+		lo, hi, s := 0.0, 1.0, t
+		for range 32 {
+			x := bezier(x1, x2, s) - t
+			if math.Abs(x) < 1e-9 {
+				break
+			}
+			if x > 0 {
+				hi = s
+			} else {
+				lo = s
+			}
+			ms := 1 - s
+			dx := 3*ms*ms*x1 + 6*ms*s*(x2-x1) + 3*s*s*(1-x2)
+			next := s - x/dx
+			if dx == 0 || next <= lo || next >= hi {
+				next = (lo + hi) / 2
+			}
+			s = next
+		}
+		return bezier(y1, y2, s)
+	}
+}
+
+// Spring returns the motion of a damped spring with unit mass that moves from 0 to 1.
+//
+// Higher stiffness oscillates faster, damping below 2*sqrt(stiffness) overshoots and
+// bounces. Time is scaled so the spring has settled to 0.1% at t=1.
+//
+//	ease := anim.Spring(100, 10)
+func Spring(stiffness, damping float64) Ease {
+	if stiffness <= 0 {
+		return Linear
+	}
+	w0 := math.Sqrt(stiffness)
+	zeta := damping / (2 * w0)
+	// position at time tau and an upper bound of its distance to the rest position
+	var position, envelope func(tau float64) float64
+	switch {
+	case zeta < 1:
+		wd := w0 * math.Sqrt(1-zeta*zeta)
+		position = func(tau float64) float64 {
+			return 1 - math.Exp(-zeta*w0*tau)*(math.Cos(wd*tau)+zeta*w0/wd*math.Sin(wd*tau))
+		}
+		envelope = func(tau float64) float64 {
+			return math.Exp(-zeta*w0*tau) / math.Sqrt(1-zeta*zeta)
+		}
+	case zeta == 1:
+		position = func(tau float64) float64 {
+			return 1 - math.Exp(-w0*tau)*(1+w0*tau)
+		}
+		envelope = func(tau float64) float64 { return 1 - position(tau) }
+	default:
+		d := w0 * math.Sqrt(zeta*zeta-1)
+		r1, r2 := -zeta*w0+d, -zeta*w0-d
+		position = func(tau float64) float64 {
+			return 1 + (r2*math.Exp(r1*tau)-r1*math.Exp(r2*tau))/(r1-r2)
+		}
+		envelope = func(tau float64) float64 { return 1 - position(tau) }
+	}
+	// Settling time by bisection
+	lo, hi := 0.0, 1.0
+	for envelope(hi) > 1e-3 && hi < 1e6 {
+		lo, hi = hi, hi*2
+	}
+	for range 64 {
+		mid := (lo + hi) / 2
+		if envelope(mid) > 1e-3 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	settle := hi
+	return func(t float64) float64 {
+		if t <= 0 {
+			return 0
+		}
+		if t >= 1 {
+			return 1
+		}
+		return position(t * settle)
+	}
+}
@@ -0,0 +1,47 @@
+package anim_test
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/setanarut/gog/v2"
+	"github.com/setanarut/gog/v2/anim"
+	"github.com/setanarut/gog/v2/shapes"
+	"github.com/setanarut/v"
+)
+
+// Eases a value between keyframes, the value holds after the last keyframe
+func ExampleTrack_At() {
+	x := anim.FloatTrack().
+		Key(0, 0, anim.InQuad).
+		Key(1, 100, nil).
+		Key(2, 50, nil)
+	fmt.Println(x.At(0.5), x.At(1.5), x.At(3))
+	// Output:
+	// 25 75 50
+}
+
+// Moves and fades a circle, the fade starts one second later
+func ExampleRender() {
+	ctx := gog.NewContext(200, 100)
+	pos := v.Vec{}
+	var fill color.Color
+	tl := new(anim.Timeline)
+	tl.Add(anim.VecTrack().
+		Key(0, v.Vec{X: 20, Y: 50}, anim.OutBounce).
+		Key(2, v.Vec{X: 180, Y: 50}, nil).
+		Bind(&pos))
+	tl.AddAt(1, anim.ColorTrack().
+		Key(0, color.White, anim.Linear).
+		Key(1, color.Black, nil).
+		Bind(&fill))
+	circle := shapes.Circle(v.Vec{}, 10)
+	anim.Render(ctx, tl, 25, func(t float64) {
+		ctx.Clear(color.Gray{60})
+		ctx.Fill(circle.SetPos(pos), fill)
+	})
+	// ctx.SaveAPNG("bounce.png", anim.Delay(25))
+	fmt.Println(len(ctx.AnimationFrames), anim.Delay(25))
+	// Output:
+	// 50 4
+}
@@ -0,0 +1,103 @@
+// Package anim animates values with keyframe tracks, easing functions and a timeline
+// that renders frames into a gog.Context.
+//
+//	x := 0.0
+//	tl := new(anim.Timeline)
+//	tl.Add(anim.FloatTrack().Key(0, 0, anim.OutBounce).Key(2, 200, nil).Bind(&x))
+//	anim.Render(ctx, tl, 50, func(t float64) {
+//		ctx.Clear(color.Black)
+//		ctx.Fill(circle.SetPos(v.Vec{X: x, Y: 50}), color.White)
+//	})
+package anim
+
+import (
+	"math"
+
+	"github.com/setanarut/gog/v2"
+)
+
+// Animator is anything that can be seeked in time, such as a Track or a Timeline
+type Animator interface {
+	// Seek updates the animated values to time in seconds
+	Seek(time float64)
+	// Duration returns the length in seconds
+	Duration() float64
+}
+
+// Timeline plays animators together, each starting at its own offset.
+// A Timeline is an Animator, so timelines can be nested.
+type Timeline struct {
+	items []timelineItem
+}
+
+type timelineItem struct {
+	start float64
+	a     Animator
+}
+
+// Add adds animators starting at time 0
+func (tl *Timeline) Add(animators ...Animator) *Timeline {
+	for _, a := range animators {
+		tl.AddAt(0, a)
+	}
+	return tl
+}
+
+// AddAt adds an animator that starts at start seconds
+func (tl *Timeline) AddAt(start float64, a Animator) *Timeline {
+	tl.items = append(tl.items, timelineItem{start, a})
+	return tl
+}
+
+// Seek seeks every animator to time relative to its start
+func (tl *Timeline) Seek(time float64) {
+	for _, it := range tl.items {
+		it.a.Seek(time - it.start)
+	}
+}
+
+// Duration returns the end time of the last animator
+func (tl *Timeline) Duration() float64 {
+	d := 0.0
+	for _, it := range tl.items {
+		d = max(d, it.start+it.a.Duration())
+	}
+	return d
+}
+
+// Frames returns the number of frames needed to play a at fps, at least 1
+func Frames(a Animator, fps float64) int {
+	return max(1, int(math.Ceil(a.Duration()*fps-1e-9)))
+}
+
+// Delay returns the frame delay in 100ths of a second for fps, as used by SaveAPNG and SaveGIF
+func Delay(fps float64) int {
+	return max(1, int(math.Round(100/fps)))
+}
+
+// Render plays a at fps. For every frame a is seeked to the frame time, draw is called
+// with the time in seconds and the canvas is appended to ctx.AnimationFrames.
+//
+// Frames(a, fps) frames are rendered, the frame at the end time is left out so loops are seamless.
+func Render(ctx *gog.Context, a Animator, fps float64, draw func(time float64)) {
+	for i := range Frames(a, fps) {
+		t := float64(i) / fps
+		a.Seek(t)
+		draw(t)
+		ctx.AppendAnimationFrame()
+	}
+}
+
+// RenderToSink is like Render but writes the frames to sink, so memory use does not
+// grow with the frame count. The sink is not closed.
+func RenderToSink(ctx *gog.Context, sink gog.FrameSink, a Animator, fps float64, draw func(time float64)) error {
+	for i := range Frames(a, fps) {
+		t := float64(i) / fps
+		a.Seek(t)
+		draw(t)
+		if err := ctx.WriteFrame(sink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package anim
+
+import (
+	"image/color"
+	"slices"
+
+	"github.com/setanarut/gog/v2/utils"
+	"github.com/setanarut/v"
+)
+
+// Keyframe is a value at a point in time
+type Keyframe[T any] struct {
+	// Time in seconds
+	Time  float64
+	Value T
+	// Ease shapes the transition from this keyframe to the next one, nil means Linear
+	Ease Ease
+}
+
+// Track interpolates a value between keyframes.
+//
+// Before the first keyframe the track holds the first value, after the last keyframe the last value.
+type Track[T any] struct {
+	// Keyframes in ascending time order
+	Keyframes []Keyframe[T]
+	// Target receives the value when the track is seeked, it may be nil
+	Target *T
+	lerp   func(a, b T, t float64) T
+}
+
+// NewTrack returns an empty track that interpolates values with lerp
+func NewTrack[T any](lerp func(a, b T, t float64) T) *Track[T] {
+	return &Track[T]{lerp: lerp}
+}
+
+// FloatTrack returns an empty track of float64 values
+func FloatTrack() *Track[float64] {
+	return NewTrack(func(a, b, t float64) float64 { return a + (b-a)*t })
+}
+
+// VecTrack returns an empty track of v.Vec values
+func VecTrack() *Track[v.Vec] {
+	return NewTrack(func(a, b v.Vec, t float64) v.Vec { return a.Lerp(b, t) })
+}
+
+// ColorTrack returns an empty track of colors, interpolated in premultiplied RGBA
+func ColorTrack() *Track[color.Color] {
+	return NewTrack(LerpColor)
+}
+
+// Key adds a keyframe keeping the keyframes in time order. ease shapes the transition to the next keyframe.
+func (tr *Track[T]) Key(time float64, value T, ease Ease) *Track[T] {
+	i, _ := slices.BinarySearchFunc(tr.Keyframes, time, func(k Keyframe[T], t float64) int {
+		if k.Time <= t {
+			return -1
+		}
+		return 1
+	})
+	tr.Keyframes = slices.Insert(tr.Keyframes, i, Keyframe[T]{Time: time, Value: value, Ease: ease})
+	return tr
+}
+
+// Bind sets the target that receives the value when the track is seeked
+func (tr *Track[T]) Bind(target *T) *Track[T] {
+	tr.Target = target
+	return tr
+}
+
+// At returns the value at time. An empty track returns the zero value.
+func (tr *Track[T]) At(time float64) T {
+	keys := tr.Keyframes
+	if len(keys) == 0 {
+		var zero T
+		return zero
+	}
+	if time <= keys[0].Time {
+		return keys[0].Value
+	}
+	last := keys[len(keys)-1]
+	if time >= last.Time {
+		return last.Value
+	}
+	i, _ := slices.BinarySearchFunc(keys, time, func(k Keyframe[T], t float64) int {
+		if k.Time <= t {
+			return -1
+		}
+		return 1
+	})
+	k0, k1 := keys[i-1], keys[i]
+	t := (time - k0.Time) / (k1.Time - k0.Time)
+	if k0.Ease != nil {
+		t = k0.Ease(t)
+	}
+	return tr.lerp(k0.Value, k1.Value, t)
+}
+
+// Seek writes the value at time to the target
+func (tr *Track[T]) Seek(time float64) {
+	if tr.Target != nil {
+		*tr.Target = tr.At(time)
+	}
+}
+
+// Duration returns the time of the last keyframe
+func (tr *Track[T]) Duration() float64 {
+	if len(tr.Keyframes) == 0 {
+		return 0
+	}
+	return tr.Keyframes[len(tr.Keyframes)-1].Time
+}
+
+// LerpColor interpolates premultiplied colors, t outside of 0-1 is clamped. It is utils.LerpColor.
+func LerpColor(c0, c1 color.Color, t float64) color.Color {
+	return utils.LerpColor(c0, c1, t)
+}
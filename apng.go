@@ -0,0 +1,479 @@
+package gog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/draw"
+	"io"
+	"math"
+	"os"
+)
+
+// APNGDispose constants determine how the frame area is treated before the next frame is drawn
+const (
+	// DisposeNone leaves the frame on the canvas
+	DisposeNone APNGDispose = iota
+	// DisposeBackground clears the frame area to transparent black
+	DisposeBackground
+	// DisposePrevious restores the frame area to its content before the frame
+	DisposePrevious
+)
+
+// APNGBlend constants determine how a frame is drawn over the canvas
+const (
+	// BlendSource replaces the pixels of the frame area
+	BlendSource APNGBlend = iota
+	// BlendOver alpha composites the frame over the canvas
+	BlendOver
+)
+
+type (
+	APNGDispose uint8
+	APNGBlend   uint8
+)
+
+var errAPNGFrameCount = errors.New("gog: APNG frame count does not match the number of written frames")
+
+// ErrAPNGDelay is returned when a frame delay is outside of the 0-65535 range of APNG
+var ErrAPNGDelay = errors.New("gog: APNG frame delay must be in the range 0-65535")
+
+// APNGOptions controls APNG encoding. The zero value loops forever and uses the
+// first frame as the default image.
+type APNGOptions struct {
+	// Delays holds per-frame delays in 100ths of a second, for holds and pauses.
+	// Frames beyond its length use the delay argument. Delays must be in the range 0-65535.
+	Delays []int
+	// Plays is the number of times the animation is played, zero loops forever
+	Plays int
+	// DeltaFrames encodes only the rectangle that changed since the previous frame,
+	// stored at its offset. Dispose and Blend are ignored, frames are drawn over the previous one.
+	DeltaFrames bool
+	// DefaultImage is shown by viewers without APNG support and is not part of the animation.
+	// Nil means the first frame is the default image. It must have the size of the frames.
+	DefaultImage image.Image
+	// Dispose is applied to every frame
+	Dispose APNGDispose
+	// Blend is applied to every frame
+	Blend APNGBlend
+}
+
+// SaveAPNG Saves APNG animation addes with AppendAnimationFrame().
+//
+// The successive delay times, one per frame, in 100ths of a second. (2 for 50 FPS, 4 for 25 FPS)
+// It returns ErrNoFrames if there is no frame.
+func (ctx *Context) SaveAPNG(filePath string, delay int) error {
+	return ctx.SaveAPNGOptions(filePath, delay, nil)
+}
+
+// EncodeAPNG writes animation frames added with AppendAnimationFrame() to w in APNG format.
+//
+// The successive delay times, one per frame, in 100ths of a second. (2 for 50 FPS, 4 for 25 FPS)
+// It returns ErrNoFrames if there is no frame.
+func (ctx *Context) EncodeAPNG(w io.Writer, delay int) error {
+	return ctx.EncodeAPNGOptions(w, delay, nil)
+}
+
+// SaveAPNGOptions saves animation frames added with AppendAnimationFrame() as APNG
+// with per-frame delays, play count and frame options. opts may be nil for defaults.
+func (ctx *Context) SaveAPNGOptions(filePath string, delay int, opts *APNGOptions) error {
+	if len(ctx.AnimationFrames) == 0 {
+		return ErrNoFrames
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	b := bufio.NewWriter(f)
+	if err := ctx.EncodeAPNGOptions(b, delay, opts); err != nil {
+		f.Close()
+		return err
+	}
+	if err := b.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// EncodeAPNGOptions writes animation frames added with AppendAnimationFrame() to w in APNG format.
+//
+// It returns ErrAPNGDelay if a delay is outside of the range 0-65535. See SaveAPNGOptions.
+func (ctx *Context) EncodeAPNGOptions(w io.Writer, delay int, opts *APNGOptions) error {
+	if len(ctx.AnimationFrames) == 0 {
+		return ErrNoFrames
+	}
+	sink := NewAPNGSink(w, len(ctx.AnimationFrames), delay, opts)
+	for _, frame := range ctx.AnimationFrames {
+		if err := sink.WriteFrame(frame); err != nil {
+			return err
+		}
+	}
+	return sink.Close()
+}
+
+// APNGSink streams frames as an animated PNG, only one frame is held in memory.
+//
+// APNG stores the frame count before the first frame. If it is not known in advance,
+// pass zero and an io.WriteSeeker such as *os.File, the count is written by Close.
+type APNGSink struct {
+	enc       *apngEncoder
+	numFrames int
+	delay     int
+	opts      APNGOptions
+	// prev is a copy of the previous frame for DeltaFrames
+	prev *image.RGBA
+	err  error
+}
+
+// NewAPNGSink returns an APNG sink writing to w.
+//
+// numFrames is the number of frames that will be written, zero means unknown.
+// delay is the frame delay in 100ths of a second. opts may be nil for defaults.
+func NewAPNGSink(w io.Writer, numFrames, delay int, opts *APNGOptions) *APNGSink {
+	s := &APNGSink{enc: &apngEncoder{w: w}, numFrames: numFrames, delay: delay}
+	if opts != nil {
+		s.opts = *opts
+	}
+	return s
+}
+
+// WriteFrame encodes img as the next frame
+func (s *APNGSink) WriteFrame(img image.Image) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.err = s.writeFrame(img)
+	return s.err
+}
+
+func (s *APNGSink) writeFrame(img image.Image) error {
+	bounds := img.Bounds()
+	index := s.enc.frames
+	delay := s.delay
+	if index < len(s.opts.Delays) {
+		delay = s.opts.Delays[index]
+	}
+	if delay < 0 || delay > math.MaxUint16 {
+		return ErrAPNGDelay
+	}
+	if index == 0 {
+		if err := s.enc.writeHeader(bounds.Size(), s.numFrames, s.opts.Plays); err != nil {
+			return err
+		}
+		if s.opts.DefaultImage != nil {
+			if s.opts.DefaultImage.Bounds().Size() != bounds.Size() {
+				return errFrameSize
+			}
+			if err := s.enc.writeDefaultImage(s.opts.DefaultImage); err != nil {
+				return err
+			}
+		}
+	} else if bounds.Size() != s.enc.size {
+		return errFrameSize
+	}
+	fc := apngFrameControl{
+		delayNum: uint16(delay),
+		delayDen: 100,
+		dispose:  uint8(s.opts.Dispose),
+		blend:    uint8(s.opts.Blend),
+	}
+	rect := bounds
+	if s.opts.DeltaFrames {
+		fc.dispose, fc.blend = uint8(DisposeNone), uint8(BlendSource)
+		if s.prev == nil {
+			s.prev = image.NewRGBA(bounds)
+		} else {
+			rect = changedRect(s.prev, img)
+			if rect.Empty() {
+				// Frames can not be empty, repeat one unchanged pixel
+				rect = image.Rectangle{Min: bounds.Min, Max: bounds.Min.Add(image.Pt(1, 1))}
+			}
+		}
+		draw.Draw(s.prev, bounds, img, bounds.Min, draw.Src)
+	}
+	return s.enc.writeFrame(img, rect, fc)
+}
+
+// Close writes the end of the file, it does not close the underlying writer
+func (s *APNGSink) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.enc.frames == 0 {
+		return ErrNoFrames
+	}
+	return s.enc.close(s.numFrames)
+}
+
+// apngFrameControl holds the fields of a fcTL chunk
+type apngFrameControl struct {
+	delayNum, delayDen uint16
+	dispose, blend     uint8
+}
+
+// apngEncoder writes PNG chunks of an animation one frame at a time
+type apngEncoder struct {
+	w      io.Writer
+	size   image.Point
+	seq    uint32
+	frames int
+	plays  int
+	// actlOffset is the file offset of the acTL chunk, it is patched when the frame count is unknown
+	actlOffset int64
+	// defaultDone is set when the IDAT default image has been written
+	defaultDone bool
+	// buffers reused for every frame
+	data    bytes.Buffer
+	current []uint8
+	prev    []uint8
+	best    []uint8
+	trial   []uint8
+}
+
+// writeHeader writes the signature, IHDR and acTL chunks
+func (e *apngEncoder) writeHeader(size image.Point, numFrames, plays int) error {
+	e.size = size
+	e.plays = plays
+	if numFrames == 0 {
+		ws, ok := e.w.(io.WriteSeeker)
+		if !ok {
+			return errors.New("gog: APNG frame count is required when the writer can not seek")
+		}
+		offset, err := ws.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		e.actlOffset = offset + 8 + 25
+	}
+	if _, err := io.WriteString(e.w, "\x89PNG\r\n\x1a\n"); err != nil {
+		return err
+	}
+	var ihdr [13]byte
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(size.X))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(size.Y))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // RGBA
+	if err := e.writeChunk("IHDR", ihdr[:]); err != nil {
+		return err
+	}
+	return e.writeChunk("acTL", actl(numFrames, plays))
+}
+
+func actl(numFrames, plays int) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[0:], uint32(numFrames))
+	binary.BigEndian.PutUint32(b[4:], uint32(plays))
+	return b[:]
+}
+
+// writeDefaultImage writes img as the default image that is not part of the animation
+func (e *apngEncoder) writeDefaultImage(img image.Image) error {
+	e.defaultDone = true
+	return e.writeImageData(img, img.Bounds(), true)
+}
+
+// writeFrame writes the rect of img as a frame at rect.Min.
+// Without a separate default image the first frame is stored in IDAT chunks.
+func (e *apngEncoder) writeFrame(img image.Image, rect image.Rectangle, fc apngFrameControl) error {
+	origin := rect.Min.Sub(img.Bounds().Min)
+	var b [26]byte
+	binary.BigEndian.PutUint32(b[0:], e.seq)
+	binary.BigEndian.PutUint32(b[4:], uint32(rect.Dx()))
+	binary.BigEndian.PutUint32(b[8:], uint32(rect.Dy()))
+	binary.BigEndian.PutUint32(b[12:], uint32(origin.X))
+	binary.BigEndian.PutUint32(b[16:], uint32(origin.Y))
+	binary.BigEndian.PutUint16(b[20:], fc.delayNum)
+	binary.BigEndian.PutUint16(b[22:], fc.delayDen)
+	b[24] = fc.dispose
+	b[25] = fc.blend
+	e.seq++
+	if err := e.writeChunk("fcTL", b[:]); err != nil {
+		return err
+	}
+	if err := e.writeImageData(img, rect, !e.defaultDone); err != nil {
+		return err
+	}
+	e.defaultDone = true
+	e.frames++
+	return nil
+}
+
+// writeImageData writes rect of img as IDAT or fdAT chunk
+func (e *apngEncoder) writeImageData(img image.Image, rect image.Rectangle, idat bool) error {
+	e.data.Reset()
+	if !idat {
+		var seq [4]byte
+		binary.BigEndian.PutUint32(seq[:], e.seq)
+		e.seq++
+		e.data.Write(seq[:])
+	}
+	zw, err := zlib.NewWriterLevel(&e.data, zlib.DefaultCompression)
+	if err != nil {
+		return err
+	}
+	rowLen := rect.Dx() * 4
+	e.current = resize(e.current, rowLen)
+	e.prev = resize(e.prev, rowLen)
+	e.best = resize(e.best, rowLen+1)
+	e.trial = resize(e.trial, rowLen+1)
+	clear(e.prev)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		straightRow(e.current, img, rect.Min.X, rect.Max.X, y)
+		filterRow(e.best, e.trial, e.current, e.prev)
+		if _, err := zw.Write(e.best); err != nil {
+			return err
+		}
+		e.current, e.prev = e.prev, e.current
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if idat {
+		return e.writeChunk("IDAT", e.data.Bytes())
+	}
+	return e.writeChunk("fdAT", e.data.Bytes())
+}
+
+// close writes IEND and patches the frame count if it was unknown
+func (e *apngEncoder) close(numFrames int) error {
+	if err := e.writeChunk("IEND", nil); err != nil {
+		return err
+	}
+	if numFrames != 0 {
+		if numFrames != e.frames {
+			return errAPNGFrameCount
+		}
+		return nil
+	}
+	ws := e.w.(io.WriteSeeker)
+	end, err := ws.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := ws.Seek(e.actlOffset, io.SeekStart); err != nil {
+		return err
+	}
+	if err := e.writeChunk("acTL", actl(e.frames, e.plays)); err != nil {
+		return err
+	}
+	_, err = ws.Seek(end, io.SeekStart)
+	return err
+}
+
+func (e *apngEncoder) writeChunk(name string, data []byte) error {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], name)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	var footer [4]byte
+	binary.BigEndian.PutUint32(footer[:], crc.Sum32())
+	for _, b := range [][]byte{header[:], data, footer[:]} {
+		if _, err := e.w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// straightRow writes row y of img between x0 and x1 as non-premultiplied RGBA
+func straightRow(dst []uint8, img image.Image, x0, x1, y int) {
+	if rgba, ok := img.(*image.RGBA); ok {
+		i := rgba.PixOffset(x0, y)
+		copy(dst, rgba.Pix[i:i+(x1-x0)*4])
+		for j := 0; j < len(dst); j += 4 {
+			if a := uint32(dst[j+3]); a != 0 && a != 255 {
+				dst[j] = uint8(uint32(dst[j]) * 255 / a)
+				dst[j+1] = uint8(uint32(dst[j+1]) * 255 / a)
+				dst[j+2] = uint8(uint32(dst[j+2]) * 255 / a)
+			}
+		}
+		return
+	}
+	for x := x0; x < x1; x++ {
+		r, g, b, a := img.At(x, y).RGBA()
+		j := (x - x0) * 4
+		if a != 0 && a != 0xffff {
+			r, g, b = r*0xffff/a, g*0xffff/a, b*0xffff/a
+		}
+		dst[j], dst[j+1], dst[j+2], dst[j+3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+	}
+}
+
+// filterRow stores the filter type and the filtered row with the smallest sum of
+// absolute differences in best, like the encoder of image/png
+func filterRow(best, trial, cur, prev []uint8) {
+	const bpp = 4
+	bestSum := -1
+	for filter := uint8(0); filter < 5; filter++ {
+		trial[0] = filter
+		out := trial[1:]
+		sum := 0
+		for i := range cur {
+			var a, b, c int
+			if i >= bpp {
+				a = int(cur[i-bpp])
+				c = int(prev[i-bpp])
+			}
+			b = int(prev[i])
+			var p int
+			switch filter {
+			case 1:
+				p = a
+			case 2:
+				p = b
+			case 3:
+				p = (a + b) / 2
+			case 4:
+				p = paeth(a, b, c)
+			}
+			d := cur[i] - uint8(p)
+			out[i] = d
+			sum += absInt8(d)
+			if bestSum >= 0 && sum >= bestSum {
+				break
+			}
+		}
+		if bestSum < 0 || sum < bestSum {
+			bestSum = sum
+			copy(best, trial)
+		}
+	}
+}
+
+func paeth(a, b, c int) int {
+	p := a + b - c
+	pa, pb, pc := abs(p-a), abs(p-b), abs(p-c)
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func absInt8(d uint8) int {
+	return abs(int(int8(d)))
+}
+
+// resize returns b with length n, reusing its storage if possible
+func resize(b []uint8, n int) []uint8 {
+	if cap(b) < n {
+		return make([]uint8, n)
+	}
+	return b[:n]
+}
@@ -0,0 +1,103 @@
+package gog
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/setanarut/gog/v2/path"
+)
+
+// Clip restricts drawing to the inside of shape, intersected with the current clip.
+//
+// The shape is transformed with the current transform and filled with its fill rule,
+// so a *path.Compound with EvenOdd rule can clip to a region with holes.
+// Anti-aliased edges of the clip are kept. Fill, Stroke, DrawImage and Clear only change
+// pixels inside the clip. Use Push and Pop to restore the previous clip.
+func (ctx *Context) Clip(s path.Shape) *Context {
+	old := ctx.state.clip
+	area := ctx.surface.Bounds()
+	if old != nil {
+		area = ctx.state.clipRect
+	}
+	mask := image.NewAlpha(ctx.surface.Bounds())
+	rect := image.Rectangle{}
+	if !area.Empty() {
+		layer := ctx.clipLayer()
+		ctx.filler.SetWinding(fillRule(s) == path.NonZero)
+		s.Trace(&fixedPen{ctx.filler, ctx.state.transform})
+		ctx.filler.SetColor(color.White)
+		ctx.setTarget(layer, area)
+		ctx.filler.Draw()
+		ctx.filler.Clear()
+		ctx.setTarget(ctx.surface, image.Rectangle{})
+
+		// Multiply the coverage with the old mask and clear the layer for the next use
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			for x := area.Min.X; x < area.Max.X; x++ {
+				i := layer.PixOffset(x, y)
+				a := layer.Pix[i+3]
+				clear(layer.Pix[i : i+4])
+				if old != nil {
+					a = uint8(uint16(a) * uint16(old.Pix[old.PixOffset(x, y)]) / 255)
+				}
+				if a == 0 {
+					continue
+				}
+				mask.Pix[mask.PixOffset(x, y)] = a
+				rect = rect.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	ctx.state.clip = mask
+	ctx.state.clipRect = rect
+	return ctx
+}
+
+// ResetClip removes the clip, drawing affects the whole canvas again
+func (ctx *Context) ResetClip() *Context {
+	ctx.state.clip = nil
+	ctx.state.clipRect = image.Rectangle{}
+	return ctx
+}
+
+// clipLayer returns the transparent layer used for clipped drawing
+func (ctx *Context) clipLayer() *image.RGBA {
+	if ctx.layer == nil {
+		ctx.layer = image.NewRGBA(ctx.surface.Bounds())
+	}
+	return ctx.layer
+}
+
+// setTarget directs the scanner to img, rendering is limited to rect unless it is empty
+func (ctx *Context) setTarget(img *image.RGBA, rect image.Rectangle) {
+	ctx.scannerFreeType.Pntr.Image = img
+	ctx.scannerFreeType.SetClip(rect)
+}
+
+// compositeLayer draws the layer on the surface through the clip mask and clears it
+func (ctx *Context) compositeLayer() {
+	r := ctx.state.clipRect
+	draw.DrawMask(ctx.surface, r, ctx.layer, r.Min, ctx.state.clip, r.Min, draw.Over)
+	draw.Draw(ctx.layer, r, image.Transparent, image.Point{}, draw.Src)
+}
+
+// clearClip replaces the pixels inside the clip with c, blending by the clip coverage
+func (ctx *Context) clearClip(c color.Color) {
+	r := ctx.state.clipRect
+	cr, cg, cb, ca := c.RGBA()
+	src := [4]uint32{cr >> 8, cg >> 8, cb >> 8, ca >> 8}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			m := uint32(ctx.state.clip.Pix[ctx.state.clip.PixOffset(x, y)])
+			if m == 0 {
+				continue
+			}
+			i := ctx.surface.PixOffset(x, y)
+			for k, s := range src {
+				d := uint32(ctx.surface.Pix[i+k])
+				ctx.surface.Pix[i+k] = uint8((s*m + d*(255-m)) / 255)
+			}
+		}
+	}
+}
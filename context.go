@@ -2,20 +2,28 @@
 package gog
 
 import (
+	"errors"
 	"image"
 	"image/color"
 	"image/draw"
+	"image/png"
+	"io"
+	"math"
 
-	"github.com/setanarut/apng"
+	"github.com/setanarut/gog/v2/affine"
 	"github.com/setanarut/gog/v2/path"
 	"github.com/setanarut/gog/v2/shapes"
 	"github.com/setanarut/gog/v2/utils"
 	"github.com/setanarut/v"
 	"github.com/srwiley/rasterx"
 	"github.com/srwiley/scanFT"
-	"golang.org/x/image/math/fixed"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
 )
 
+// ErrNoFrames is returned when an animation is saved before any frame is added
+var ErrNoFrames = errors.New("gog: no animation frames, add at least one frame with AppendAnimationFrame()")
+
 var yellow = color.RGBA{255, 255, 0, 255}
 var orangered = color.RGBA{255, 69, 0, 255}
 
@@ -31,6 +39,29 @@ var debugBBoxCenterStrokeStyle = &StrokeStyle{
 	Color:     color.RGBA{255, 128, 0, 255}, // orange
 	LineWidth: 2,
 }
+var debugHullStrokeStyle = &StrokeStyle{
+	Color:     color.RGBA{0, 255, 0, 255}, // green
+	LineWidth: 1,
+}
+var debugCircleStrokeStyle = &StrokeStyle{
+	Color:     color.RGBA{64, 128, 255, 255}, // blue
+	LineWidth: 1,
+}
+var debugOrientedBoxStrokeStyle = &StrokeStyle{
+	Color:     color.RGBA{255, 255, 0, 255}, // yellow
+	LineWidth: 1,
+	Join:      MiterJoin,
+}
+
+// DebugOptions selects the bounding shapes drawn by DebugDrawOptions
+type DebugOptions struct {
+	// ConvexHull draws the convex hull in green
+	ConvexHull bool
+	// EnclosingCircle draws the minimum enclosing circle in blue
+	EnclosingCircle bool
+	// OrientedBox draws the minimum area oriented bounding box in yellow
+	OrientedBox bool
+}
 
 type Context struct {
 	AnimationFrames []image.Image
@@ -41,7 +72,21 @@ type Context struct {
 	painter         *scanFT.RGBAPainter
 	scannerFreeType *scanFT.ScannerFT
 	filler          *rasterx.Filler
-	stroker         *rasterx.Stroker
+	stroker         *rasterx.Dasher
+	// layer receives drawing while a clip is active
+	layer *image.RGBA
+	state drawState
+	stack []drawState
+}
+
+// drawState is the part of the Context saved by Push and restored by Pop
+type drawState struct {
+	// transform maps drawing coordinates to canvas pixels
+	transform affine.Matrix
+	// clip is the coverage mask of the clip region, nil means no clip
+	clip *image.Alpha
+	// clipRect bounds the non-zero pixels of clip
+	clipRect image.Rectangle
 }
 
 // NewContext returns a new drawing context.
@@ -50,75 +95,125 @@ func NewContext(width, height int) *Context {
 	ctx.surface = image.NewRGBA(image.Rect(0, 0, width, height))
 	ctx.painter = scanFT.NewRGBAPainter(ctx.surface)
 	ctx.scannerFreeType = scanFT.NewScannerFT(width, height, ctx.painter)
-	ctx.stroker = rasterx.NewStroker(width, height, ctx.scannerFreeType)
+	ctx.stroker = rasterx.NewDasher(width, height, ctx.scannerFreeType)
 	ctx.filler = &ctx.stroker.Filler
 	ctx.Center = v.Vec{float64(width) / 2, float64(height) / 2}
+	ctx.state.transform = affine.Identity
 	ctx.Clear(color.Black)
 	return ctx
 }
 
-// Fill draws path with fillColoræ
-func (ctx *Context) Fill(p *path.Path, fillColor color.Color) {
-	ctx.filler.Start(utils.ToFixed(p.Start()))
-	for _, pt := range p.Points {
-		ctx.filler.Line(utils.ToFixed(pt))
-	}
-	ctx.filler.SetColor(fillColor)
-	ctx.filler.Stop(p.IsClosed())
-	ctx.filler.Draw()
-	ctx.filler.Clear()
-}
-
-// Stroke draw paths with StrokeStyle
-func (ctx *Context) Stroke(p *path.Path, strokeStyle *StrokeStyle) {
-	var capFunction rasterx.CapFunc
-	var joinStyle rasterx.JoinMode
-
-	switch strokeStyle.Cap {
-	case ButtCap:
-		capFunction = rasterx.ButtCap
-	case SquareCap:
-		capFunction = rasterx.SquareCap
-	case RoundCap:
-		capFunction = rasterx.RoundCap
-	case CubicCap:
-		capFunction = rasterx.CubicCap
-	case QuadraticCap:
-		capFunction = rasterx.QuadraticCap
+// Push saves the current transform and clip, Pop restores them
+func (ctx *Context) Push() *Context {
+	ctx.stack = append(ctx.stack, ctx.state)
+	return ctx
+}
+
+// Pop restores the transform and clip saved by the last Push. Pop without a matching Push is ignored.
+func (ctx *Context) Pop() *Context {
+	if n := len(ctx.stack); n > 0 {
+		ctx.state = ctx.stack[n-1]
+		ctx.stack = ctx.stack[:n-1]
 	}
+	return ctx
+}
 
-	switch strokeStyle.Join {
-	case MiterJoin:
-		joinStyle = rasterx.Miter
-	case RoundJoin:
-		joinStyle = rasterx.Round
-	case BevelJoin:
-		joinStyle = rasterx.Bevel
+// Translate moves the origin of the drawing coordinates
+func (ctx *Context) Translate(x, y float64) *Context {
+	ctx.state.transform = ctx.state.transform.Translate(x, y)
+	return ctx
+}
 
-	}
+// Rotate rotates the drawing coordinates about the origin, angle in radians
+func (ctx *Context) Rotate(angle float64) *Context {
+	ctx.state.transform = ctx.state.transform.Rotate(angle)
+	return ctx
+}
+
+// Scale scales the drawing coordinates about the origin
+func (ctx *Context) Scale(x, y float64) *Context {
+	ctx.state.transform = ctx.state.transform.Scale(x, y)
+	return ctx
+}
+
+// Skew skews the drawing coordinates, angles in radians
+func (ctx *Context) Skew(angleX, angleY float64) *Context {
+	ctx.state.transform = ctx.state.transform.Skew(angleX, angleY)
+	return ctx
+}
+
+// SetTransform replaces the current transform. Use affine.Identity to reset it.
+func (ctx *Context) SetTransform(m affine.Matrix) *Context {
+	ctx.state.transform = m
+	return ctx
+}
+
+// Transform returns the current transform
+func (ctx *Context) Transform() affine.Matrix {
+	return ctx.state.transform
+}
+
+// Fill draws shape with fillColor. fillColor may be a Pattern such as a LinearGradient.
+//
+// A *path.Compound is filled in one pass using its FillRule, so subpaths can cut holes.
+// The shape is drawn with the current transform, it is not modified.
+func (ctx *Context) Fill(s path.Shape, fillColor color.Color) {
+	ctx.filler.SetWinding(fillRule(s) == path.NonZero)
+	ctx.rasterize(ctx.filler, s, fillColor)
+}
+
+// Stroke draw shape outlines with StrokeStyle
+//
+// The shape is drawn with the current transform. Line width and dashes are scaled by
+// the average scale factor of the transform, so non-uniform scaling does not distort them.
+func (ctx *Context) Stroke(s path.Shape, strokeStyle *StrokeStyle) {
+	scale := math.Sqrt(math.Abs(ctx.state.transform.Det()))
+	strokeStyle.outline().SetStroke(ctx.stroker, scale)
+
+	// Stroke outlines overlap themselves, only non-zero winding fills them solid
+	ctx.stroker.SetWinding(true)
+	ctx.rasterize(ctx.stroker, s, strokeStyle.Color)
+}
 
-	ctx.stroker.SetStroke(
-		fixed.Int26_6(strokeStyle.LineWidth*64), // line width
-		fixed.Int26_6(3*64),                     // miter limit
-		capFunction,                             // cap L
-		capFunction,                             // cap T
-		rasterx.RoundGap,                        // gap
-		joinStyle)                               // join mode
-
-	ctx.stroker.Start(utils.ToFixed(p.Start()))
-	for i := 1; i < len(p.Points); i++ {
-		ctx.stroker.Line(utils.ToFixed(p.Points[i]))
+// rasterize traces shape with the current transform and draws it through the current clip
+func (ctx *Context) rasterize(r rasterizer, s path.Shape, c color.Color) {
+	if ctx.state.clip != nil && ctx.state.clipRect.Empty() {
+		return
+	}
+	s.Trace(&fixedPen{r, ctx.state.transform})
+	r.SetColor(paint(c, ctx.state.transform))
+	if ctx.state.clip == nil {
+		r.Draw()
+		r.Clear()
+		return
 	}
+	ctx.setTarget(ctx.clipLayer(), ctx.state.clipRect)
+	r.Draw()
+	r.Clear()
+	ctx.setTarget(ctx.surface, image.Rectangle{})
+	ctx.compositeLayer()
+}
 
-	ctx.stroker.SetColor(strokeStyle.Color)
-	ctx.stroker.Stop(p.IsClosed())
-	ctx.stroker.Draw()
-	ctx.stroker.Clear()
+// DrawImage draws img with its top left corner at pos, using the current transform and clip.
+//
+// Transformed images are resampled with bilinear filtering.
+func (ctx *Context) DrawImage(img image.Image, pos v.Vec) {
+	b := img.Bounds()
+	m := ctx.state.transform.Translate(pos.X-float64(b.Min.X), pos.Y-float64(b.Min.Y))
+	opts := &xdraw.Options{}
+	if ctx.state.clip != nil {
+		opts.DstMask = ctx.state.clip
+	}
+	s2d := f64.Aff3{m.A, m.C, m.E, m.B, m.D, m.F}
+	xdraw.BiLinear.Transform(ctx.surface, s2d, img, b, xdraw.Over, opts)
 }
 
-// Clear clears canvas
+// Clear fills the canvas with color c, only the clip region is changed if a clip is active
 func (ctx *Context) Clear(c color.Color) *Context {
-	// m := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	if ctx.state.clip != nil {
+		ctx.clearClip(c)
+		return ctx
+	}
 	draw.Draw(ctx.surface, ctx.surface.Bounds(),
 		&image.Uniform{c}, image.Point{}, draw.Src)
 	return ctx
@@ -135,8 +230,13 @@ func (ctx *Context) ClearAnimationFrames() {
 }
 
 // SavePNG saves current canvas as static image
-func (ctx *Context) SavePNG(filePath string) {
-	utils.WritePNG(filePath, ctx.surface)
+func (ctx *Context) SavePNG(filePath string) error {
+	return utils.WritePNG(filePath, ctx.surface)
+}
+
+// EncodePNG writes current canvas to w in PNG format
+func (ctx *Context) EncodePNG(w io.Writer) error {
+	return png.Encode(w, ctx.surface)
 }
 
 // Surface returns canvas surface image
@@ -144,18 +244,26 @@ func (ctx *Context) Surface() *image.RGBA {
 	return ctx.surface
 }
 
-// SaveAPNG Saves APNG animation addes with AppendAnimationFrame().
-//
-// The successive delay times, one per frame, in 100ths of a second. (2 for 50 FPS, 4 for 25 FPS)
-func (ctx *Context) SaveAPNG(filePath string, delay int) {
-	if len(ctx.AnimationFrames) == 0 {
-		panic("There is no frame in the image sequence, add at least one frame with AppendAnimationFrame().")
-	}
-	apng.Save(filePath, ctx.AnimationFrames, uint16(delay))
-}
-
 // DebugDraw draws Path attributes for debug
 func (ctx *Context) DebugDraw(pth *path.Path) {
+	ctx.DebugDrawOptions(pth, nil)
+}
+
+// DebugDrawOptions draws Path attributes for debug like DebugDraw, with the bounding shapes
+// selected by opts. opts may be nil.
+func (ctx *Context) DebugDrawOptions(pth *path.Path, opts *DebugOptions) {
+	if opts != nil {
+		if opts.ConvexHull {
+			ctx.Stroke(pth.ConvexHull(), debugHullStrokeStyle)
+		}
+		if opts.EnclosingCircle {
+			center, radius := pth.MinEnclosingCircle()
+			ctx.Stroke(shapes.Circle(center, radius), debugCircleStrokeStyle)
+		}
+		if opts.OrientedBox {
+			ctx.Stroke(pth.OrientedBounds().Path(), debugOrientedBoxStrokeStyle)
+		}
+	}
 
 	// Draw Bounding box
 	ctx.Stroke(shapes.BBox(pth.Bounds()), debugStyle)
@@ -188,3 +296,70 @@ func (ctx *Context) DebugDraw(pth *path.Path) {
 	ctx.Stroke(circle, debugBBoxCenterStrokeStyle)
 
 }
+
+// paint returns the scanner color source, Patterns are sampled at pixel centers
+// mapped back to drawing coordinates, so they move with the shape.
+func paint(c color.Color, m affine.Matrix) interface{} {
+	if p, ok := c.(Pattern); ok {
+		return rasterx.ColorFunc(pixelSampler(p, m))
+	}
+	return c
+}
+
+// pixelSampler returns the color of p at the center of canvas pixel x, y drawn with transform m
+func pixelSampler(p Pattern, m affine.Matrix) func(x, y int) color.Color {
+	inv := m.Invert()
+	colorAt := p.ColorAt
+	if s, ok := p.(sampledPattern); ok {
+		colorAt = s.sampler()
+	}
+	return func(x, y int) color.Color {
+		pt := inv.Apply(v.Vec{X: float64(x) + 0.5, Y: float64(y) + 0.5})
+		return colorAt(pt.X, pt.Y)
+	}
+}
+
+// rasterizer is implemented by rasterx.Filler and rasterx.Dasher
+type rasterizer interface {
+	rasterx.Adder
+	SetColor(color interface{})
+	Draw()
+	Clear()
+}
+
+// fillRule returns the fill rule of the shape, only compound shapes have a selectable rule.
+func fillRule(s path.Shape) path.FillRule {
+	if c, ok := s.(*path.Compound); ok {
+		return c.FillRule
+	}
+	return path.NonZero
+}
+
+// fixedPen transforms path commands with m and converts them to rasterx fixed point commands
+type fixedPen struct {
+	adder rasterx.Adder
+	m     affine.Matrix
+}
+
+func (p *fixedPen) Start(pt v.Vec) {
+	p.adder.Start(utils.ToFixed(p.m.Apply(pt)))
+}
+
+func (p *fixedPen) Line(pt v.Vec) {
+	p.adder.Line(utils.ToFixed(p.m.Apply(pt)))
+}
+
+func (p *fixedPen) QuadBezier(ctrl, pt v.Vec) {
+	p.adder.QuadBezier(utils.ToFixed(p.m.Apply(ctrl)), utils.ToFixed(p.m.Apply(pt)))
+}
+
+func (p *fixedPen) CubeBezier(ctrl1, ctrl2, pt v.Vec) {
+	p.adder.CubeBezier(
+		utils.ToFixed(p.m.Apply(ctrl1)),
+		utils.ToFixed(p.m.Apply(ctrl2)),
+		utils.ToFixed(p.m.Apply(pt)))
+}
+
+func (p *fixedPen) Stop(closed bool) {
+	p.adder.Stop(closed)
+}
@@ -1,9 +1,18 @@
 package gog_test
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
+	"hash/crc32"
 	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
 	"math"
+	"os"
+	"strings"
 
 	"github.com/setanarut/gog/v2"
 	"github.com/setanarut/gog/v2/path"
@@ -84,3 +93,600 @@ func ExamplePath_RemoveDoubles() {
 	// Output:
 	// [(0.0, 0.0) (77.0, 77.0) (0.0, 0.0)]
 }
+
+// Records a filled square and a stroked line as SVG
+func ExampleSVGContext() {
+	svg := gog.NewSVGContext(40, 40)
+	svg.Fill(shapes.Square(v.Vec{X: 10, Y: 10}, 20), color.RGBA{255, 0, 0, 255})
+	svg.Stroke(shapes.Line(v.Vec{X: 0, Y: 0}, v.Vec{X: 40, Y: 40}), gog.DefaultStrokeStyle())
+	svg.EncodeSVG(os.Stdout)
+	// Output:
+	// <svg xmlns="http://www.w3.org/2000/svg" width="40" height="40" viewBox="0 0 40 40">
+	// <path d="M10 10 L30 10 L30 30 L10 30 Z" fill="#ff0000"/>
+	// <path d="M0 0 L40 40" fill="none" stroke="#ffffff" stroke-width="1.5" stroke-linecap="butt" stroke-linejoin="miter" stroke-miterlimit="3"/>
+	// </svg>
+}
+
+// Records transformed and clipped drawing, points and line widths are written in canvas coordinates
+func ExampleSVGContext_Push() {
+	svg := gog.NewSVGContext(40, 40)
+	svg.Push().Translate(20, 20).Scale(2, 2)
+	svg.Clip(shapes.Rect(v.Vec{X: -5, Y: -5}, 10, 5))
+	svg.Stroke(shapes.Line(v.Vec{X: -5, Y: 0}, v.Vec{X: 5, Y: 0}), gog.DefaultStrokeStyle())
+	svg.Pop()
+	svg.Fill(shapes.Rect(v.Vec{X: 0, Y: 0}, 10, 10), color.White)
+	svg.EncodeSVG(os.Stdout)
+	// Output:
+	// <svg xmlns="http://www.w3.org/2000/svg" width="40" height="40" viewBox="0 0 40 40">
+	// <defs>
+	// <clipPath id="clip1"><path d="M10 10 L30 10 L30 20 L10 20 Z"/></clipPath>
+	// </defs>
+	// <path d="M10 20 L30 20" fill="none" stroke="#ffffff" stroke-width="3" stroke-linecap="butt" stroke-linejoin="miter" stroke-miterlimit="3" clip-path="url(#clip1)"/>
+	// <path d="M0 0 L10 0 L10 10 L0 10 Z" fill="#ffffff"/>
+	// </svg>
+}
+
+// Fills a donut, the inner circle becomes a hole with EvenOdd fill rule
+func ExampleContext_Fill_compound() {
+	ctx := gog.NewContext(100, 100)
+	donut := path.NewCompound(
+		shapes.Circle(ctx.Center, 40),
+		shapes.Circle(ctx.Center, 20),
+	).SetFillRule(path.EvenOdd)
+	ctx.Fill(donut, color.White)
+	fmt.Println(ctx.Surface().At(50, 50), ctx.Surface().At(50, 20))
+	// Output:
+	// {0 0 0 255} {255 255 255 255}
+}
+
+// Samples a reflected linear gradient
+func ExampleLinearGradient() {
+	g := gog.NewLinearGradient(v.Vec{X: 0, Y: 0}, v.Vec{X: 100, Y: 0},
+		gog.ColorStop{Offset: 0, Color: color.Black},
+		gog.ColorStop{Offset: 1, Color: color.White},
+	)
+	g.Spread = gog.ReflectSpread
+	fmt.Println(g.ColorAt(25, 0), g.ColorAt(175, 0))
+	// Output:
+	// {16383 16383 16383 65535} {16383 16383 16383 65535}
+}
+
+// With the focus outside of the circle, points behind the focus are outside of the cone and stay transparent
+func ExampleRadialGradient() {
+	g := gog.NewRadialGradient(v.Vec{X: 0, Y: 0}, 10,
+		gog.ColorStop{Offset: 0, Color: color.Black},
+		gog.ColorStop{Offset: 1, Color: color.White},
+	)
+	g.Focus = v.Vec{X: 30, Y: 0}
+	fmt.Println(g.ColorAt(20, 0), g.ColorAt(50, 0), g.ColorAt(30, 20))
+	// Output:
+	// {32767 32767 32767 65535} {0} {0}
+}
+
+// Exports a gradient that fades to transparent, translucent stops get stop-opacity
+func ExampleSVGContext_gradient() {
+	svg := gog.NewSVGContext(40, 40)
+	fade := gog.NewLinearGradient(v.Vec{X: 0, Y: 0}, v.Vec{X: 40, Y: 0},
+		gog.ColorStop{Offset: 0, Color: color.RGBA{255, 0, 0, 255}},
+		gog.ColorStop{Offset: 1, Color: color.NRGBA{0, 0, 255, 128}},
+	)
+	svg.Fill(shapes.Square(v.Vec{X: 0, Y: 0}, 40), fade)
+	svg.EncodeSVG(os.Stdout)
+	// Output:
+	// <svg xmlns="http://www.w3.org/2000/svg" width="40" height="40" viewBox="0 0 40 40">
+	// <defs>
+	// <linearGradient id="gradient1" gradientUnits="userSpaceOnUse" x1="0" y1="0" x2="40" y2="0"><stop offset="0" stop-color="#ff0000"/><stop offset="1" stop-color="#0000ff" stop-opacity="0.502"/></linearGradient>
+	// </defs>
+	// <path d="M0 0 L40 0 L40 40 L0 40 Z" fill="url(#gradient1)"/>
+	// </svg>
+}
+
+// SVG has no conic gradient, it is rasterized into an image pattern that matches Context
+func ExampleSVGContext_conic() {
+	sweep := gog.NewConicGradient(v.Vec{X: 20, Y: 20}, 0,
+		gog.ColorStop{Offset: 0, Color: color.Black},
+		gog.ColorStop{Offset: 1, Color: color.White},
+	)
+	svg := gog.NewSVGContext(40, 40)
+	svg.Fill(shapes.Square(v.Vec{X: 0, Y: 0}, 40), sweep)
+	var sb strings.Builder
+	svg.EncodeSVG(&sb)
+	doc := sb.String()
+	fmt.Println(strings.Contains(doc, `<pattern id="pattern1" patternUnits="userSpaceOnUse" width="40" height="40">`))
+	fmt.Println(strings.Contains(doc, `fill="url(#pattern1)"`))
+
+	// The embedded image has the colors Context draws
+	data, _, _ := strings.Cut(doc[strings.Index(doc, "base64,")+7:], `"`)
+	raw, _ := base64.StdEncoding.DecodeString(data)
+	img, _ := png.Decode(bytes.NewReader(raw))
+	ctx := gog.NewContext(40, 40)
+	ctx.Fill(shapes.Square(v.Vec{X: 0, Y: 0}, 40), sweep)
+	for _, pt := range [][2]int{{30, 20}, {20, 30}, {10, 20}, {20, 10}} {
+		r, _, _, _ := img.At(pt[0], pt[1]).RGBA()
+		fmt.Println(r>>8, ctx.Surface().RGBAAt(pt[0], pt[1]).R)
+	}
+	// Output:
+	// true
+	// true
+	// 1 1
+	// 62 62
+	// 125 125
+	// 194 194
+}
+
+// A gradient built as a struct literal has the zero Transform, which is the identity
+func ExampleLinearGradient_literal() {
+	g := &gog.LinearGradient{
+		Start: v.Vec{X: 0, Y: 0},
+		End:   v.Vec{X: 100, Y: 0},
+		Gradient: gog.Gradient{Stops: []gog.ColorStop{
+			{Offset: 0, Color: color.Black},
+			{Offset: 1, Color: color.White},
+		}},
+	}
+	ctx := gog.NewContext(100, 10)
+	ctx.Fill(shapes.Rect(v.Vec{X: 0, Y: 0}, 100, 10), g)
+	fmt.Println(ctx.Surface().At(10, 5), ctx.Surface().At(90, 5))
+	svg := gog.NewSVGContext(100, 10)
+	svg.Fill(shapes.Rect(v.Vec{X: 0, Y: 0}, 100, 10), g)
+	var b bytes.Buffer
+	svg.EncodeSVG(&b)
+	fmt.Println(strings.Contains(b.String(), "gradientTransform"))
+	// Output:
+	// {26 26 26 255} {231 231 231 255}
+	// false
+}
+
+// Curves keep true arc segments, so length is exact and flattening adapts to tolerance
+func ExampleCurve() {
+	quarter := shapes.Arc(v.Vec{X: 0, Y: 0}, 100, 0, math.Pi/2)
+	fmt.Printf("%.6f %.6f\n", quarter.Length(), math.Pi*50)
+	pt, angle := quarter.PointAngleAtLength(quarter.Length() / 2)
+	fmt.Println(pt, math.Round(angle*1000)/1000)
+	fmt.Println(quarter.Flatten(1).Len(), quarter.Flatten(0.01).Len())
+	// Output:
+	// 157.079633 157.079633
+	// (70.7, 70.7) 2.356
+	// 7 57
+}
+
+// Dash offsets wrap around the pattern, a negative offset moves the dashes forward like stroke-dashoffset
+func ExampleStrokeStyle_SetDashOffset() {
+	line := shapes.Line(v.Vec{X: 0, Y: 5}, v.Vec{X: 60, Y: 5})
+	// dashes returns the x coordinates where the dashes start
+	dashes := func(offset float64) []int {
+		ctx := gog.NewContext(60, 10)
+		ctx.Stroke(line, gog.DefaultStrokeStyle().SetLineWidth(4).SetDashes(10, 10).SetDashOffset(offset))
+		var starts []int
+		for x := range 60 {
+			on := ctx.Surface().RGBAAt(x, 5).R > 127
+			if on && (x == 0 || ctx.Surface().RGBAAt(x-1, 5).R <= 127) {
+				starts = append(starts, x)
+			}
+		}
+		return starts
+	}
+	fmt.Println(dashes(0), dashes(5))
+	fmt.Println(dashes(-5), dashes(15), dashes(1e6+15))
+	// Odd patterns repeat with dashes and gaps swapped, their period is twice the sum
+	ctx := gog.NewContext(60, 10)
+	ctx.Stroke(line, gog.DefaultStrokeStyle().SetLineWidth(4).SetDashes(10).SetDashOffset(-25))
+	fmt.Println(ctx.Surface().RGBAAt(2, 5).R, ctx.Surface().RGBAAt(7, 5).R)
+	// Output:
+	// [0 20 40] [0 15 35 55]
+	// [5 25 45] [5 25 45] [5 25 45]
+	// 0 255
+}
+
+// Draws a square with the transform stack, the path itself is not modified
+func ExampleContext_Push() {
+	ctx := gog.NewContext(100, 100)
+	square := shapes.Square(v.Vec{X: -5, Y: -5}, 10)
+	ctx.Push().Translate(50, 50).Rotate(math.Pi/4).Scale(2, 2)
+	ctx.Fill(square, color.White)
+	ctx.Pop()
+	fmt.Println(square.Start(), ctx.Transform().IsIdentity())
+	fmt.Println(ctx.Surface().At(50, 40), ctx.Surface().At(40, 40))
+	// Output:
+	// (-5.0, -5.0) true
+	// {255 255 255 255} {0 0 0 255}
+}
+
+// Draws hatch lines only inside a circle
+func ExampleContext_Clip() {
+	ctx := gog.NewContext(100, 100)
+	ctx.Push()
+	ctx.Clip(shapes.Circle(ctx.Center, 40))
+	for x := -100.0; x < 100; x += 10 {
+		ctx.Stroke(shapes.Line(v.Vec{X: x, Y: 0}, v.Vec{X: x + 100, Y: 100}), gog.DefaultStrokeStyle().SetLineWidth(4))
+	}
+	ctx.Pop()
+	fmt.Println(ctx.Surface().At(50, 50), ctx.Surface().At(5, 5))
+	// Output:
+	// {255 255 255 255} {0 0 0 255}
+}
+
+// Encodes the canvas to memory, saving an animation without frames returns an error
+func ExampleContext_EncodePNG() {
+	ctx := gog.NewContext(10, 10)
+	var buf bytes.Buffer
+	if err := ctx.EncodePNG(&buf); err != nil {
+		panic(err)
+	}
+	fmt.Println(buf.Len() > 0)
+	fmt.Println(ctx.EncodeAPNG(&buf, 3) == gog.ErrNoFrames)
+	// Output:
+	// true
+	// true
+}
+
+// Streams frames into a sink instead of keeping them in memory
+func ExampleContext_WriteFrame() {
+	ctx := gog.NewContext(2, 2)
+	var buf bytes.Buffer
+	sink := gog.NewPPMSink(&buf)
+	for range 2 {
+		ctx.Clear(color.White)
+		if err := ctx.WriteFrame(sink); err != nil {
+			panic(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		panic(err)
+	}
+	fmt.Println(buf.Len(), bytes.Count(buf.Bytes(), []byte("P6")))
+	// Output:
+	// 46 2
+}
+
+// Plays the animation three times, loop counts that do not fit in a GIF are rejected
+func ExampleContext_EncodeGIF() {
+	ctx := gog.NewContext(4, 4)
+	ctx.AppendAnimationFrame()
+	ctx.Clear(color.White)
+	ctx.AppendAnimationFrame()
+	err := ctx.EncodeGIF(io.Discard, 10, &gog.GIFOptions{LoopCount: 70000})
+	fmt.Println(err == gog.ErrGIFLoopCount)
+
+	var buf bytes.Buffer
+	if err := ctx.EncodeGIF(&buf, 10, &gog.GIFOptions{LoopCount: 2}); err != nil {
+		panic(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(g.Image), g.LoopCount)
+	// Output:
+	// true
+	// 2 2
+}
+
+// Unchanged delta frames add their delay to the previous frame, past the GIF limit of 65535
+// the delay continues in a 1x1 frame
+func ExampleGIFSink() {
+	ctx := gog.NewContext(4, 4)
+	var buf bytes.Buffer
+	sink := gog.NewGIFSink(&buf, 40000, &gog.GIFOptions{DeltaFrames: true})
+	for i := range 4 {
+		if i == 3 {
+			ctx.Fill(shapes.Rect(v.Vec{X: 2, Y: 2}, 2, 2), color.White)
+		}
+		if err := ctx.WriteFrame(sink); err != nil {
+			panic(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		panic(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		panic(err)
+	}
+	for i, frame := range g.Image {
+		fmt.Println(frame.Rect, g.Delay[i])
+	}
+	// Output:
+	// (0,0)-(4,4) 40000
+	// (0,0)-(1,1) 40000
+	// (0,0)-(1,1) 40000
+	// (2,2)-(4,4) 40000
+}
+
+// Holds the last frame for one second and plays the animation twice
+func ExampleContext_EncodeAPNGOptions() {
+	ctx := gog.NewContext(50, 50)
+	circle := shapes.Circle(v.Vec{X: 10, Y: 25}, 5)
+	for range 4 {
+		ctx.Clear(color.Black)
+		ctx.Fill(circle.Translate(8, 0), color.White)
+		ctx.AppendAnimationFrame()
+	}
+	var buf bytes.Buffer
+	err := ctx.EncodeAPNGOptions(&buf, 4, &gog.APNGOptions{
+		Delays:      []int{4, 4, 4, 100},
+		Plays:       2,
+		DeltaFrames: true,
+	})
+	fmt.Println(err)
+	err = ctx.EncodeAPNGOptions(&buf, 4, &gog.APNGOptions{Delays: []int{4, 70000}})
+	fmt.Println(err == gog.ErrAPNGDelay)
+	// Output:
+	// <nil>
+	// true
+}
+
+// Walks the chunks of an APNG with delta frames and decodes its default image
+func ExampleContext_EncodeAPNGOptions_chunks() {
+	ctx := gog.NewContext(50, 50)
+	square := shapes.Square(v.Vec{X: 0, Y: 20}, 10)
+	for range 3 {
+		ctx.Clear(color.Black)
+		ctx.Fill(square.Translate(10, 0), color.White)
+		ctx.AppendAnimationFrame()
+	}
+	var buf bytes.Buffer
+	if err := ctx.EncodeAPNGOptions(&buf, 4, &gog.APNGOptions{Delays: []int{10, 20}, Plays: 3, DeltaFrames: true}); err != nil {
+		fmt.Println(err)
+	}
+	data := buf.Bytes()[8:]
+	seq := uint32(0)
+	for len(data) > 0 {
+		n := binary.BigEndian.Uint32(data)
+		typ, body := string(data[4:8]), data[8:8+n]
+		if crc32.ChecksumIEEE(data[4:8+n]) != binary.BigEndian.Uint32(data[8+n:]) {
+			fmt.Println("bad CRC in", typ)
+		}
+		if typ == "fcTL" || typ == "fdAT" {
+			if binary.BigEndian.Uint32(body) != seq {
+				fmt.Println("bad sequence number in", typ)
+			}
+			seq++
+		}
+		switch typ {
+		case "acTL":
+			fmt.Printf("acTL frames=%d plays=%d\n", binary.BigEndian.Uint32(body), binary.BigEndian.Uint32(body[4:]))
+		case "fcTL":
+			be := binary.BigEndian
+			fmt.Printf("fcTL size=%dx%d offset=%d,%d delay=%d/%d\n", be.Uint32(body[4:]), be.Uint32(body[8:]),
+				be.Uint32(body[12:]), be.Uint32(body[16:]), be.Uint16(body[20:]), be.Uint16(body[22:]))
+		case "IDAT", "fdAT", "IEND":
+			fmt.Println(typ)
+		}
+		data = data[12+n:]
+	}
+	img, err := png.Decode(&buf)
+	fmt.Println(img.Bounds(), img.At(15, 25), err)
+	// Output:
+	// acTL frames=3 plays=3
+	// fcTL size=50x50 offset=0,0 delay=10/100
+	// IDAT
+	// fcTL size=20x10 offset=10,20 delay=20/100
+	// fdAT
+	// fcTL size=20x10 offset=20,20 delay=4/100
+	// fdAT
+	// IEND
+	// (0,0)-(50,50) {255 255 255 255} <nil>
+}
+
+// Evenly spaced points by arc length
+func ExamplePath_Resample() {
+	line := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 10, Y: 0}})
+	line.Resample(5)
+	line.PrintPoints()
+	square := shapes.Square(v.Vec{}, 10).ResampleBySpacing(5)
+	fmt.Println(square.Len(), square.IsClosed())
+	// Output:
+	// [(0.0, 0.0) (2.5, 0.0) (5.0, 0.0) (7.5, 0.0) (10.0, 0.0)]
+	// 9 true
+}
+
+// Samples a path many times without rescanning its segments
+func ExampleArcLength() {
+	circle := shapes.Circle(v.Vec{X: 0, Y: 0}, 100)
+	arc := circle.ArcLength()
+	fmt.Printf("%.1f %.4f\n", arc.Length(), math.Abs(arc.CurvatureAtT(0.3)))
+	fmt.Println(arc.PointAtT(0), arc.PointAtT(1.25).Dist(arc.PointAtT(0.25)) < 1e-9)
+	circle.Scale(v.Vec{X: 2, Y: 2})
+	fmt.Printf("%.1f\n", arc.Length())
+	// Output:
+	// 628.2 0.0100
+	// (100.0, 0.0) true
+	// 1256.3
+}
+
+// Combines two overlapping squares
+func ExampleBoolean() {
+	a := shapes.Square(v.Vec{X: 0, Y: 0}, 10)
+	b := shapes.Square(v.Vec{X: 5, Y: 5}, 10)
+	path.Intersection(a, b).Paths[0].PrintPoints()
+	fmt.Println(path.Union(a, b).Paths[0].Len(), path.Xor(a, b).Len())
+	// Output:
+	// [(10.0, 5.0) (10.0, 10.0) (5.0, 10.0) (5.0, 5.0) (10.0, 5.0)]
+	// 9 2
+}
+
+// Grows a square with mitered corners and shrinks it
+func ExamplePath_Offset() {
+	square := shapes.Square(v.Vec{X: 0, Y: 0}, 100)
+	square.Offset(10, path.MiterJoin, 0).Paths[0].PrintPoints()
+	square.Offset(-10, path.MiterJoin, 0).Paths[0].PrintPoints()
+	// Output:
+	// [(110.0, -10.0) (110.0, 110.0) (-10.0, 110.0) (-10.0, -10.0) (110.0, -10.0)]
+	// [(10.0, 10.0) (90.0, 10.0) (90.0, 90.0) (10.0, 90.0) (10.0, 10.0)]
+}
+
+// Converts a stroke to a fillable outline, the square becomes a frame with a hole
+func ExampleStrokeToPath() {
+	square := shapes.Square(v.Vec{X: 50, Y: 50}, 100)
+	outline := gog.StrokeToPath(square, gog.DefaultStrokeStyle().SetLineWidth(20))
+	for _, p := range outline.Paths {
+		p.PrintPoints()
+	}
+	// Output:
+	// [(140.0, 60.0) (60.0, 60.0) (60.0, 140.0) (140.0, 140.0) (140.0, 60.0)]
+	// [(160.0, 40.0) (160.0, 160.0) (40.0, 160.0) (40.0, 40.0) (160.0, 40.0)]
+}
+
+// Removes points of an over-dense curve and rounds the corners of a square
+func ExamplePath_Simplify() {
+	curve := shapes.CubicBezier(0, 0, 50, 100, 150, -100, 200, 0, 200)
+	fmt.Println(curve.Len(), curve.Clone().Simplify(0.5).Len(), curve.Clone().SimplifyVW(1).Len())
+	square := shapes.Square(v.Vec{X: 0, Y: 0}, 100).Chaikin(1)
+	square.PrintPoints()
+	line := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 20, Y: 0}})
+	line.SmoothLaplacian(1, 0.5).PrintPoints()
+	// Output:
+	// 200 19 32
+	// [(25.0, 0.0) (75.0, 0.0) (100.0, 25.0) (100.0, 75.0) (75.0, 100.0) (25.0, 100.0) (0.0, 75.0) (0.0, 25.0) (25.0, 0.0)]
+	// [(0.0, 0.0) (10.0, 5.0) (20.0, 0.0)]
+}
+
+// Area, true centroid and containment of an unevenly sampled square
+func ExamplePath_Moments() {
+	square := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}})
+	m := square.Moments()
+	fmt.Println(square.SignedArea(), square.IsClockwise(), square.Centroid(), m.Centroid)
+	fmt.Printf("%.2f %.2f %.2f\n", m.Ixx, m.Iyy, m.Ixy)
+	fmt.Println(square.WindingNumber(v.Vec{X: 5, Y: 5}), square.Contains(v.Vec{X: 11, Y: 5}, path.NonZero))
+	pt, length := square.ClosestPoint(v.Vec{X: 12, Y: 4})
+	fmt.Println(pt, length, square.Distance(v.Vec{X: 12, Y: 4}))
+	// Output:
+	// 100 true (3.3, 2.9) (5.0, 5.0)
+	// 833.33 833.33 0.00
+	// 1 false
+	// (10.0, 4.0) 14 2
+}
+
+// Finds where a line crosses a zigzag and where a path loops over itself
+func ExampleIntersections() {
+	line := path.NewPath([]v.Vec{{X: 0, Y: 5}, {X: 30, Y: 5}})
+	zigzag := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 20, Y: 0}, {X: 30, Y: 10}})
+	for _, c := range path.Intersections(line, zigzag) {
+		fmt.Printf("%v %d %d %.2f %.2f\n", c.Point, c.IndexA, c.IndexB, c.LengthA, c.LengthB)
+	}
+	loop := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 0, Y: 10}})
+	fmt.Println(loop.SelfIntersections())
+	// Folding back along the same line overlaps the previous segment
+	fold := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 0}})
+	fmt.Println(fold.SelfIntersections())
+	fmt.Println(len(shapes.Square(v.Vec{X: 0, Y: 0}, 10).SelfIntersections()), len(shapes.Circle(v.Vec{X: 0, Y: 0}, 10).SelfIntersections()))
+	// Output:
+	// (5.0, 5.0) 0 0 5.00 7.07
+	// (15.0, 5.0) 0 1 15.00 21.21
+	// (25.0, 5.0) 0 2 25.00 35.36
+	// [{(5.0, 5.0) 0 2 7.0710678118654755 31.213203435596427}]
+	// [{(5.0, 0.0) 0 1 5 15}]
+	// 0 0
+}
+
+// Cuts a path by arc length, sub paths of closed paths wrap around the start point
+func ExamplePath_SubPath() {
+	line := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 20, Y: 0}})
+	a, b := line.SplitAtLength(5)
+	fmt.Println(a.Points, b.Points)
+	fmt.Println(line.SubPath(0.25, 0.75).Points)
+	square := shapes.Square(v.Vec{X: 0, Y: 0}, 10)
+	fmt.Println(square.SubPath(0.875, 0.125).Points)
+	fmt.Println(square.Clone().Trim(5, 5).Points)
+	// Output:
+	// [(0.0, 0.0) (5.0, 0.0)] [(5.0, 0.0) (10.0, 0.0) (20.0, 0.0)]
+	// [(5.0, 0.0) (10.0, 0.0) (15.0, 0.0)]
+	// [(0.0, 5.0) (0.0, 0.0) (5.0, 0.0)]
+	// [(5.0, 0.0)]
+}
+
+// Tweens a square into a triangle, both are resampled to the same point count
+func ExampleMorph() {
+	square := shapes.Square(v.Vec{X: 0, Y: 0}, 10)
+	triangle := path.NewPath([]v.Vec{{X: 20, Y: 0}, {X: 30, Y: 10}, {X: 20, Y: 10}, {X: 20, Y: 0}})
+	m := path.NewMorph(square, triangle, 40)
+	m.Ease = func(t float64) float64 { return t * t }
+	for _, t := range []float64{0, 0.5, 1} {
+		p := m.At(t)
+		fmt.Printf("%d %.1f\n", p.Len(), p.Area())
+	}
+	// Output:
+	// 41 100.0
+	// 41 88.6
+	// 41 49.9
+}
+
+// The ends of a morph reproduce its input paths, a closed path morphed into an open one stays closed at t=0
+func ExampleMorph_ends() {
+	square := shapes.Square(v.Vec{X: 0, Y: 0}, 10)
+	diamond := path.NewPath([]v.Vec{{X: 30, Y: 0}, {X: 40, Y: 10}, {X: 30, Y: 20}, {X: 20, Y: 10}, {X: 30, Y: 0}})
+	line := path.NewPath([]v.Vec{{X: 0, Y: 20}, {X: 40, Y: 20}})
+	// farthest returns the largest distance from the points of p to target
+	farthest := func(p, target *path.Path) float64 {
+		d := 0.0
+		for _, pt := range p.Points {
+			d = max(d, target.Distance(pt))
+		}
+		return d
+	}
+	// 8 points around the loops, a closed path morphed as open also gets its closing point
+	for _, target := range []*path.Path{diamond, line} {
+		samples := 8
+		if !target.IsClosed() {
+			samples = 9
+		}
+		m := path.NewMorph(square, target, samples)
+		start, end := m.At(0), m.At(1)
+		fmt.Printf("%d %v %.2f %.2f\n", start.Len(), start.IsClosed(), start.Length(), farthest(start, square))
+		fmt.Printf("%d %v %.2f %.2f\n", end.Len(), end.IsClosed(), end.Length(), farthest(end, target))
+	}
+	// Output:
+	// 9 true 40.00 0.00
+	// 9 true 56.57 0.00
+	// 9 true 40.00 0.00
+	// 9 false 40.00 0.00
+}
+
+// Bounding shapes of a triangle
+func ExamplePath_OrientedBounds() {
+	triangle := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 40, Y: 0}, {X: 20, Y: 10}, {X: 10, Y: 5}, {X: 0, Y: 0}})
+	fmt.Println(triangle.ConvexHull().Points)
+	center, radius := triangle.MinEnclosingCircle()
+	fmt.Printf("%v %.1f\n", center, radius)
+	box := triangle.OrientedBounds()
+	fmt.Printf("%v %v %.2f\n", box.Center, box.Size, box.Area())
+	// Output:
+	// [(0.0, 0.0) (40.0, 0.0) (20.0, 10.0) (0.0, 0.0)]
+	// (20.0, 0.0) 20.0
+	// (20.0, 5.0) (40.0, 10.0) 400.00
+}
+
+// Hulls turn from +X towards +Y and leave out collinear points, points on a line give an open hull
+// and a box without height
+func ExamplePath_ConvexHull() {
+	// A square given clockwise on the screen with midpoints on its edges
+	square := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 0, Y: 5}, {X: 0, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 0}, {X: 5, Y: 0}})
+	hull := square.ConvexHull()
+	fmt.Println(hull.Points, hull.IsClosed(), hull.Area())
+
+	line := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 10}, {X: 5, Y: 5}, {X: 20, Y: 20}})
+	fmt.Println(line.ConvexHull().Points, line.ConvexHull().IsClosed())
+	box := line.OrientedBounds()
+	fmt.Printf("%v %.2f %.2f %.4f\n", box.Center, box.Size.X, box.Size.Y, box.Angle)
+
+	// An acute triangle needs all three points on its circle, an obtuse one only its longest side
+	acute := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 8}})
+	center, radius := acute.MinEnclosingCircle()
+	fmt.Printf("%v %.4f\n", center, radius)
+	obtuse := path.NewPath([]v.Vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 1}})
+	center, radius = obtuse.MinEnclosingCircle()
+	fmt.Printf("%v %.4f\n", center, radius)
+
+	// A rectangle rotated by 30 degrees gets back its own box
+	rect := shapes.Rect(v.Vec{X: 0, Y: 0}, 40, 10).Rotate(math.Pi / 6)
+	box = path.NewCompound(rect).OrientedBounds()
+	fmt.Printf("%.2f %.2f %.2f\n", box.Size.X, box.Size.Y, box.Area())
+	// Output:
+	// [(0.0, 0.0) (10.0, 0.0) (10.0, 10.0) (0.0, 10.0) (0.0, 0.0)] true 100
+	// [(0.0, 0.0) (20.0, 20.0)] false
+	// (10.0, 10.0) 28.28 0.00 0.7854
+	// (5.0, 2.4) 5.5625
+	// (5.0, 0.0) 5.0000
+	// 40.00 10.00 400.00
+}
@@ -16,7 +16,7 @@ func main() {
 	for i := 0; i < 150; i++ {
 		ctx.Clear(color.Gray{30})
 		bezierPath.Rotate((math.Pi * 2) / 150)
-		ctx.DebugDraw(bezierPath)
+		ctx.DebugDrawOptions(bezierPath, &gog.DebugOptions{ConvexHull: true, EnclosingCircle: true, OrientedBox: true})
 		ctx.AppendAnimationFrame()
 	}
 	ctx.SaveAPNG("bezier_anim.png", 3)
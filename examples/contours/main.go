@@ -0,0 +1,25 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/setanarut/gog/v2"
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/gog/v2/shapes"
+	"github.com/setanarut/v"
+)
+
+func main() {
+	ctx := gog.NewContext(250, 250)
+	ctx.Clear(color.Black)
+	// Two overlapping circles merged into one outline
+	blob := path.Union(
+		shapes.Circle(v.Vec{X: 100, Y: 125}, 45),
+		shapes.Circle(v.Vec{X: 155, Y: 125}, 30),
+	)
+	strokeStyle := gog.DefaultStrokeStyle().SetLineWidth(1)
+	for i := -4; i <= 6; i++ {
+		ctx.Stroke(blob.Offset(float64(i)*8, path.RoundJoin, 0), strokeStyle)
+	}
+	ctx.SavePNG("contours.png")
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/setanarut/gog/v2"
+	"github.com/setanarut/gog/v2/shapes"
+	"github.com/setanarut/v"
+)
+
+func main() {
+	ctx := gog.NewContext(450, 150)
+	stops := []gog.ColorStop{
+		{Offset: 0, Color: color.RGBA{255, 69, 0, 255}},
+		{Offset: 0.5, Color: color.RGBA{255, 255, 0, 255}},
+		{Offset: 1, Color: color.RGBA{30, 144, 255, 255}},
+	}
+	circle := shapes.Circle(v.Vec{}, 60)
+
+	linear := gog.NewLinearGradient(v.Vec{X: 15, Y: 15}, v.Vec{X: 135, Y: 135}, stops...)
+	ctx.Fill(circle.SetPos(v.Vec{X: 75, Y: 75}), linear)
+
+	radial := gog.NewRadialGradient(v.Vec{X: 225, Y: 75}, 30, stops...)
+	radial.Focus = v.Vec{X: 210, Y: 60}
+	radial.Spread = gog.ReflectSpread
+	ctx.Fill(circle.SetPos(v.Vec{X: 225, Y: 75}), radial)
+
+	conic := gog.NewConicGradient(v.Vec{X: 375, Y: 75}, 0,
+		gog.ColorStop{Offset: 0, Color: stops[0].Color},
+		gog.ColorStop{Offset: 0.5, Color: stops[2].Color},
+		gog.ColorStop{Offset: 1, Color: stops[0].Color},
+	)
+	ctx.Stroke(circle.SetPos(v.Vec{X: 375, Y: 75}), gog.DefaultStrokeStyle().SetLineWidth(20).SetColor(conic))
+	ctx.SavePNG("gradient.png")
+}
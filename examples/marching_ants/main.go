@@ -0,0 +1,21 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/setanarut/gog/v2"
+	"github.com/setanarut/gog/v2/shapes"
+)
+
+func main() {
+	ctx := gog.NewContext(250, 250)
+	star := shapes.RegularPolygon(ctx.Center, 5, 90)
+	strokeStyle := gog.DefaultStrokeStyle().SetLineWidth(4).SetDashes(10, 6)
+	strokeStyle.Cap = gog.RoundCap
+	for i := range 32 {
+		ctx.Clear(color.Black)
+		ctx.Stroke(star, strokeStyle.SetDashOffset(float64(i)/2))
+		ctx.AppendAnimationFrame()
+	}
+	ctx.SaveAPNG("marching_ants.png", 3)
+}
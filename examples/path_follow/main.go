@@ -13,11 +13,11 @@ func main() {
 	ctx := gog.NewContext(250, 100)
 	rect := shapes.Rect(v.Vec{}, 30, 10)
 	lemn := shapes.Lemniscate(100, 100).SetPos(ctx.Center)
-	lemnTotalLength := lemn.Length()
-	for _, length := range utils.Linspace(0, lemnTotalLength, 120) {
+	lemnArc := lemn.ArcLength()
+	for _, length := range utils.Linspace(0, lemnArc.Length(), 120) {
 		ctx.Clear(color.Black)
 		ctx.Stroke(lemn, gog.DefaultStrokeStyle())
-		pos, ang := lemn.PointAngleAtLength(length)
+		pos, ang := lemnArc.PointAngleAtLength(length)
 		ctx.Fill(rect.SetPos(pos).Rotated(ang), color.White)
 		ctx.AppendAnimationFrame()
 	}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/setanarut/gog/v2"
+	"github.com/setanarut/gog/v2/font"
+	"github.com/setanarut/gog/v2/shapes"
+	"github.com/setanarut/v"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func main() {
+	f, err := font.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+	ctx := gog.NewContext(400, 300)
+
+	title := f.Face(48)
+	title.Align = font.AlignCenter
+	title.VerticalAlign = font.Middle
+	ctx.FillText(title, "Hello, GOG!", v.Vec{X: ctx.Center.X, Y: 60}, color.White)
+
+	// Text along a circle
+	circle := shapes.Arc(v.Vec{X: ctx.Center.X, Y: 190}, 80, math.Pi, math.Pi)
+	ctx.Stroke(circle, gog.DefaultStrokeStyle().SetColor(color.Gray{80}))
+	label := f.Face(20)
+	label.Align = font.AlignCenter
+	label.LetterSpacing = 2
+	ctx.Fill(label.TextOnPath("text follows any path", circle, circle.Length()/2), color.RGBA{255, 200, 0, 255})
+
+	// Transformed and stroked text
+	ctx.Push().Translate(ctx.Center.X, 250).Skew(-0.3, 0)
+	ctx.StrokeText(title, "Outline", v.Vec{}, gog.DefaultStrokeStyle().SetColor(color.RGBA{0, 200, 255, 255}))
+	ctx.Pop()
+
+	ctx.SavePNG("text.png")
+}
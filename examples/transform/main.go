@@ -0,0 +1,31 @@
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/setanarut/gog/v2"
+	"github.com/setanarut/gog/v2/shapes"
+	"github.com/setanarut/v"
+)
+
+func main() {
+	ctx := gog.NewContext(250, 250)
+	// The square is created once around the origin and never modified
+	square := shapes.Square(v.Vec{X: -10, Y: -10}, 20)
+	strokeStyle := gog.DefaultStrokeStyle()
+	for frame := range 60 {
+		ctx.Clear(color.Black)
+		ctx.Push().Translate(ctx.Center.X, ctx.Center.Y)
+		for i := range 12 {
+			ctx.Push()
+			ctx.Rotate(float64(i)*math.Pi/6 + float64(frame)*math.Pi/180)
+			ctx.Translate(80, 0).Scale(1, 1+float64(i)/6)
+			ctx.Stroke(square, strokeStyle)
+			ctx.Pop()
+		}
+		ctx.Pop()
+		ctx.AppendAnimationFrame()
+	}
+	ctx.SaveAPNG("transform.png", 3)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"image/color"
+	"math/rand/v2"
+
+	"github.com/setanarut/gog/v2"
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/gog/v2/shapes"
+	"github.com/setanarut/gog/v2/voronoi"
+	"github.com/setanarut/v"
+)
+
+func main() {
+	ctx := gog.NewContext(250, 250)
+	ctx.Clear(color.Black)
+	bounds := shapes.Circle(ctx.Center, 110)
+	var points []v.Vec
+	for len(points) < 60 {
+		pt := v.Vec{X: rand.Float64() * 250, Y: rand.Float64() * 250}
+		if bounds.Contains(pt, path.NonZero) {
+			points = append(points, pt)
+		}
+	}
+	d := voronoi.NewDiagram(voronoi.Relax(points, bounds, 5), bounds)
+	for _, cell := range d.Cells {
+		if cell.Path == nil {
+			continue
+		}
+		// Cells with many neighbors are lighter
+		gray := uint8(min(40*len(cell.Neighbors), 255))
+		ctx.Fill(cell.Path.Offset(-2, path.RoundJoin, 0), color.RGBA{gray, 80, 255 - gray, 255})
+		ctx.Fill(shapes.Circle(cell.Site, 1.5), color.White)
+	}
+	strokeStyle := gog.DefaultStrokeStyle().SetLineWidth(0.5)
+	for _, edge := range d.Triangulation.EdgePaths() {
+		ctx.Stroke(edge, strokeStyle)
+	}
+	ctx.SavePNG("voronoi.png")
+}
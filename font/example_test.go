@@ -0,0 +1,31 @@
+package font_test
+
+import (
+	"fmt"
+
+	"github.com/setanarut/gog/v2/font"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Measures and lays out centered text
+func ExampleFace_Glyphs() {
+	f, err := font.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+	face := f.Face(32)
+	face.Align = font.AlignCenter
+	width, height := face.Measure("AV")
+	fmt.Printf("%.2f %.2f\n", width, height)
+	face.LetterSpacing = 4
+	width, _ = face.Measure("AV")
+	fmt.Printf("%.2f\n", width)
+	for _, g := range face.Glyphs("AV") {
+		fmt.Printf("%c %.2f %d\n", g.Rune, g.Origin.X, len(g.Shape.Paths))
+	}
+	// Output:
+	// 42.69 36.98
+	// 46.69
+	// A -23.34 2
+	// V 2.00 1
+}
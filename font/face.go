@@ -0,0 +1,191 @@
+package font
+
+import (
+	"strings"
+
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/v"
+	"golang.org/x/image/font/sfnt"
+)
+
+// Align constants determine the horizontal alignment of each line relative to the origin
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// VerticalAlign constants determine which part of the text block is placed at the origin
+const (
+	// Baseline puts the baseline of the first line at the origin
+	Baseline VerticalAlign = iota
+	// Top puts the ascent of the first line at the origin
+	Top
+	// Middle puts the vertical center of the text block at the origin
+	Middle
+	// Bottom puts the descent of the last line at the origin
+	Bottom
+)
+
+type (
+	Align         uint8
+	VerticalAlign uint8
+)
+
+// Face is a Font at a size with layout options
+type Face struct {
+	Font *Font
+	// Size in pixels per em
+	Size float64
+	// Align is the horizontal alignment of lines
+	Align Align
+	// VerticalAlign is the vertical alignment of the text block
+	VerticalAlign VerticalAlign
+	// Kerning enables the pair adjustments of the font
+	Kerning bool
+	// LetterSpacing is added between glyphs, in pixels
+	LetterSpacing float64
+	// LineHeight is the distance between baselines as a multiple of the font line height, zero means 1
+	LineHeight float64
+	// Tolerance is the maximum deviation of flattened outlines in pixels, zero means path.DefaultTolerance
+	Tolerance float64
+}
+
+// Glyph is a positioned glyph of a laid out text
+type Glyph struct {
+	Rune rune
+	// Shape holds the outline at its final position, it has no paths for blank glyphs such as space.
+	// The anchor is at the baseline center of the glyph, use it to rotate or scale single letters.
+	Shape *path.Compound
+	// Origin is the pen position on the baseline
+	Origin v.Vec
+	// Advance is the distance to the next glyph origin, kerning and letter spacing not included
+	Advance float64
+}
+
+// Guide is a path that text can follow, it is implemented by *path.Path and *path.Curve
+type Guide interface {
+	Length() float64
+	PointAngleAtLength(length float64) (v.Vec, float64)
+}
+
+// Glyphs lays out s and returns its glyphs, lines are separated by '\n'.
+//
+// The text is aligned to the origin (0, 0) with Align and VerticalAlign.
+func (f *Face) Glyphs(s string) []Glyph {
+	lines := strings.Split(s, "\n")
+	ascent, descent, lineHeight := f.Font.metrics(f.Size)
+	if f.LineHeight != 0 {
+		lineHeight *= f.LineHeight
+	}
+	y := 0.0
+	blockHeight := ascent + descent + lineHeight*float64(len(lines)-1)
+	switch f.VerticalAlign {
+	case Top:
+		y = ascent
+	case Middle:
+		y = ascent - blockHeight/2
+	case Bottom:
+		y = ascent - blockHeight
+	}
+	var glyphs []Glyph
+	for _, line := range lines {
+		lineGlyphs, width := f.layoutLine(line)
+		dx := 0.0
+		switch f.Align {
+		case AlignCenter:
+			dx = -width / 2
+		case AlignRight:
+			dx = -width
+		}
+		for _, g := range lineGlyphs {
+			g.Origin = g.Origin.Add(v.Vec{X: dx, Y: y})
+			g.Shape.Translate(dx, y)
+			glyphs = append(glyphs, g)
+		}
+		y += lineHeight
+	}
+	return glyphs
+}
+
+// Text returns the outline of s as one shape, lines are separated by '\n'.
+//
+// The anchor of the shape is the alignment origin, so SetPos places the text
+// by its alignment point.
+func (f *Face) Text(s string) *path.Compound {
+	text := &path.Compound{}
+	for _, g := range f.Glyphs(s) {
+		text.AppendPaths(g.Shape.Paths...)
+	}
+	return text
+}
+
+// TextOnPath returns the outline of s with every glyph placed on guide and rotated to its tangent.
+//
+// offset is the arc length where the alignment origin is placed, for example with AlignCenter
+// the text is centered at offset. Glyphs whose center falls outside of the guide are dropped.
+// Line breaks are treated as spaces.
+func (f *Face) TextOnPath(s string, guide Guide, offset float64) *path.Compound {
+	text := &path.Compound{}
+	total := guide.Length()
+	for _, g := range f.Glyphs(strings.ReplaceAll(s, "\n", " ")) {
+		length := offset + g.Shape.Anchor.X
+		if length < 0 || length > total {
+			continue
+		}
+		pos, angle := guide.PointAngleAtLength(length)
+		// Anchor on the alignment line keeps the vertical alignment relative to the guide
+		g.Shape.SetAnchor(v.Vec{X: g.Shape.Anchor.X}).SetPos(pos).Rotate(angle)
+		text.AppendPaths(g.Shape.Paths...)
+	}
+	return text
+}
+
+// Measure returns the width of the widest line and the height of the text block
+func (f *Face) Measure(s string) (width, height float64) {
+	lines := strings.Split(s, "\n")
+	for _, line := range lines {
+		_, w := f.layoutLine(line)
+		width = max(width, w)
+	}
+	ascent, descent, lineHeight := f.Font.metrics(f.Size)
+	if f.LineHeight != 0 {
+		lineHeight *= f.LineHeight
+	}
+	return width, ascent + descent + lineHeight*float64(len(lines)-1)
+}
+
+// layoutLine lays out a single line starting at the origin and returns its glyphs and width
+func (f *Face) layoutLine(line string) ([]Glyph, float64) {
+	tolerance := f.Tolerance
+	if tolerance == 0 {
+		tolerance = path.DefaultTolerance
+	}
+	var glyphs []Glyph
+	x := 0.0
+	var prev sfnt.GlyphIndex
+	for i, r := range []rune(line) {
+		index := f.Font.glyphIndex(r)
+		if i > 0 {
+			x += f.LetterSpacing
+			if f.Kerning {
+				x += f.Font.kern(prev, index, f.Size)
+			}
+		}
+		advance := f.Font.advance(index, f.Size)
+		contours := f.Font.outline(index, f.Size, tolerance)
+		paths := make([]*path.Path, len(contours))
+		for i, c := range contours {
+			paths[i] = c.Clone().Translate(x, 0)
+		}
+		glyphs = append(glyphs, Glyph{
+			Rune:    r,
+			Shape:   &path.Compound{Anchor: v.Vec{X: x + advance/2}, Paths: paths},
+			Origin:  v.Vec{X: x},
+			Advance: advance,
+		})
+		x += advance
+		prev = index
+	}
+	return glyphs, x
+}
@@ -0,0 +1,188 @@
+// Package font converts text to paths using TrueType and OpenType fonts.
+//
+// Glyph outlines are flattened to *path.Path contours and combined into a *path.Compound,
+// so text can be filled, stroked, transformed and animated like any other shape.
+package font
+
+import (
+	"os"
+
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/v"
+	xfont "golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font is a parsed TrueType or OpenType font.
+//
+// Glyph outlines are cached, Font is not safe for concurrent use.
+type Font struct {
+	sfnt *sfnt.Font
+	buf  sfnt.Buffer
+	ppem fixed.Int26_6
+	// curves holds glyph outlines in font units, one entry per glyph
+	curves map[sfnt.GlyphIndex][]*path.Curve
+	// cache holds flattened outlines, it is cleared when it reaches maxCachedOutlines
+	cache map[glyphKey][]*path.Path
+}
+
+// maxCachedOutlines bounds the flattened outline cache, so animating the size does not
+// grow it without limit
+const maxCachedOutlines = 1024
+
+// glyphKey identifies a flattened glyph outline
+type glyphKey struct {
+	index     sfnt.GlyphIndex
+	size      float64
+	tolerance float64
+}
+
+// Load reads a TTF or OTF font file
+func Load(filePath string) (*Font, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses TTF or OTF font data, such as golang.org/x/image/font/gofont/goregular.TTF
+func Parse(data []byte) (*Font, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	// Outlines, advances and kerning are read in font units and scaled in float64
+	return &Font{
+		sfnt:   f,
+		ppem:   fixed.I(int(f.UnitsPerEm())),
+		curves: make(map[sfnt.GlyphIndex][]*path.Curve),
+		cache:  make(map[glyphKey][]*path.Path),
+	}, nil
+}
+
+// Name returns the full font name, or an empty string if the font has no name
+func (f *Font) Name() string {
+	name, err := f.sfnt.Name(&f.buf, sfnt.NameIDFull)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// Face returns a Face of the Font with the given size in pixels per em, kerning is enabled
+func (f *Font) Face(size float64) *Face {
+	return &Face{Font: f, Size: size, Kerning: true}
+}
+
+// unitScale returns the factor from font units to pixels at size
+func (f *Font) unitScale(size float64) float64 {
+	return size / float64(f.sfnt.UnitsPerEm())
+}
+
+// glyphIndex returns the glyph of r, missing glyphs return the .notdef glyph 0
+func (f *Font) glyphIndex(r rune) sfnt.GlyphIndex {
+	x, err := f.sfnt.GlyphIndex(&f.buf, r)
+	if err != nil {
+		return 0
+	}
+	return x
+}
+
+// advance returns the horizontal advance of glyph x in pixels
+func (f *Font) advance(x sfnt.GlyphIndex, size float64) float64 {
+	adv, err := f.sfnt.GlyphAdvance(&f.buf, x, f.ppem, xfont.HintingNone)
+	if err != nil {
+		return 0
+	}
+	return fromFixed(adv) * f.unitScale(size)
+}
+
+// kern returns the kerning adjustment between glyphs x0 and x1 in pixels
+func (f *Font) kern(x0, x1 sfnt.GlyphIndex, size float64) float64 {
+	k, err := f.sfnt.Kern(&f.buf, x0, x1, f.ppem, xfont.HintingNone)
+	if err != nil {
+		return 0
+	}
+	return fromFixed(k) * f.unitScale(size)
+}
+
+// metrics returns ascent, descent and line height in pixels
+func (f *Font) metrics(size float64) (ascent, descent, height float64) {
+	m, err := f.sfnt.Metrics(&f.buf, f.ppem, xfont.HintingNone)
+	if err != nil {
+		return size, 0, size
+	}
+	s := f.unitScale(size)
+	return fromFixed(m.Ascent) * s, fromFixed(m.Descent) * s, fromFixed(m.Height) * s
+}
+
+// outline returns the flattened contours of glyph x at size with the origin at (0, 0).
+//
+// Glyphs that can not be loaded, such as color emoji, have no contours.
+// The returned paths are shared by the cache and must be cloned before modification.
+func (f *Font) outline(x sfnt.GlyphIndex, size, tolerance float64) []*path.Path {
+	key := glyphKey{x, size, tolerance}
+	if contours, ok := f.cache[key]; ok {
+		return contours
+	}
+	if len(f.cache) >= maxCachedOutlines {
+		clear(f.cache)
+	}
+	// Flattening in font units with a scaled tolerance keeps the error within tolerance pixels
+	s := f.unitScale(size)
+	var contours []*path.Path
+	for _, c := range f.glyphCurves(x) {
+		p := c.Flatten(tolerance / s)
+		for i, pt := range p.Points {
+			p.Points[i] = pt.Scale(s)
+		}
+		p.Anchor = p.Anchor.Scale(s)
+		contours = append(contours, p)
+	}
+	f.cache[key] = contours
+	return contours
+}
+
+// glyphCurves returns the contours of glyph x in font units, they are loaded once per glyph
+func (f *Font) glyphCurves(x sfnt.GlyphIndex) []*path.Curve {
+	if curves, ok := f.curves[x]; ok {
+		return curves
+	}
+	segments, err := f.sfnt.LoadGlyph(&f.buf, x, f.ppem, nil)
+	if err != nil {
+		f.curves[x] = nil
+		return nil
+	}
+	pt := func(p fixed.Point26_6) v.Vec {
+		return v.Vec{X: fromFixed(p.X), Y: fromFixed(p.Y)}
+	}
+	var curves []*path.Curve
+	var curve *path.Curve
+	flush := func() {
+		if curve != nil && len(curve.Segments) > 0 {
+			curves = append(curves, curve.Close())
+		}
+	}
+	for _, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			flush()
+			curve = path.NewCurve(pt(seg.Args[0]))
+		case sfnt.SegmentOpLineTo:
+			curve.LineTo(pt(seg.Args[0]))
+		case sfnt.SegmentOpQuadTo:
+			curve.QuadTo(pt(seg.Args[0]), pt(seg.Args[1]))
+		case sfnt.SegmentOpCubeTo:
+			curve.CubeTo(pt(seg.Args[0]), pt(seg.Args[1]), pt(seg.Args[2]))
+		}
+	}
+	flush()
+	f.curves[x] = curves
+	return curves
+}
+
+func fromFixed(x fixed.Int26_6) float64 {
+	return float64(x) / 64
+}
@@ -0,0 +1,416 @@
+package gog
+
+import (
+	"bufio"
+	"compress/lzw"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"os"
+
+	"github.com/setanarut/gog/v2/quantize"
+)
+
+// DitherMode constants determine how colors missing from a GIF palette are approximated
+const (
+	// NoDither maps every pixel to the closest palette color
+	NoDither DitherMode = iota
+	// FloydSteinbergDither diffuses the error to neighbor pixels, best for still frames and photos
+	FloydSteinbergDither
+	// OrderedDither uses a fixed Bayer pattern that does not flicker between frames
+	OrderedDither
+)
+
+type DitherMode uint8
+
+// ErrGIFLoopCount is returned when GIFOptions.LoopCount is outside of the range -1 to 65535
+var ErrGIFLoopCount = errors.New("gog: GIF loop count must be in the range -1 to 65535")
+
+// GIFOptions controls GIF encoding. The zero value builds one shared 256 color palette
+// with median-cut quantization, without dithering, looping forever.
+type GIFOptions struct {
+	// Quantizer builds the palettes, nil means quantize.MedianCut{}.
+	// quantize.Octree{} keeps small areas of distinct colors better.
+	Quantizer draw.Quantizer
+	// NumColors is the palette size in the range 2-256, zero means 256
+	NumColors int
+	// Palette is used for all frames when set, no palette is built
+	Palette color.Palette
+	// PerFramePalette builds a palette for every frame instead of one shared palette
+	PerFramePalette bool
+	// Dither is the dithering method
+	Dither DitherMode
+	// LoopCount controls repetition, 0 loops forever, -1 plays once and n plays n+1 times.
+	// Values outside of -1 to 65535 return ErrGIFLoopCount.
+	LoopCount int
+	// DeltaFrames encodes only the rectangle that changed since the previous frame.
+	// Frames without change are merged into the previous frame by adding their delay.
+	// A merged delay that would pass the GIF limit of 65535 continues in a 1x1 frame that repeats a pixel.
+	DeltaFrames bool
+}
+
+// SaveGIF saves animation frames added with AppendAnimationFrame() as an animated GIF.
+//
+// The successive delay times, one per frame, in 100ths of a second. (2 for 50 FPS, 4 for 25 FPS)
+// opts may be nil for defaults. It returns ErrNoFrames if there is no frame and ErrGIFLoopCount
+// if LoopCount is out of range.
+func (ctx *Context) SaveGIF(filePath string, delay int, opts *GIFOptions) error {
+	if len(ctx.AnimationFrames) == 0 {
+		return ErrNoFrames
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := ctx.EncodeGIF(f, delay, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// EncodeGIF writes animation frames added with AppendAnimationFrame() to w as an animated GIF.
+//
+// See SaveGIF. Unless a Palette or PerFramePalette is set, the shared palette is built from all frames.
+func (ctx *Context) EncodeGIF(w io.Writer, delay int, opts *GIFOptions) error {
+	frames := ctx.AnimationFrames
+	if len(frames) == 0 {
+		return ErrNoFrames
+	}
+	o := GIFOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Palette == nil && !o.PerFramePalette {
+		o.Palette = o.quantizer().Quantize(make(color.Palette, 0, o.numColors()), quantize.Frames(frames))
+	}
+	sink := NewGIFSink(w, delay, &o)
+	for _, frame := range frames {
+		if err := sink.WriteFrame(frame); err != nil {
+			return err
+		}
+	}
+	return sink.Close()
+}
+
+func (o *GIFOptions) quantizer() draw.Quantizer {
+	if o.Quantizer == nil {
+		return quantize.MedianCut{}
+	}
+	return o.Quantizer
+}
+
+func (o *GIFOptions) numColors() int {
+	if o.NumColors == 0 {
+		return 256
+	}
+	return min(256, max(2, o.NumColors))
+}
+
+func (o *GIFOptions) drawer() draw.Drawer {
+	switch o.Dither {
+	case FloydSteinbergDither:
+		return draw.FloydSteinberg
+	case OrderedDither:
+		return quantize.Ordered
+	}
+	return quantize.Nearest
+}
+
+// GIFSink streams frames as an animated GIF, memory use does not grow with the frame count.
+//
+// Without GIFOptions.Palette or PerFramePalette, the shared palette is built from the first frame.
+type GIFSink struct {
+	w       *bufio.Writer
+	delay   int
+	opts    GIFOptions
+	palette color.Palette
+	size    image.Point
+	// prev is a copy of the previous source frame for DeltaFrames
+	prev *image.RGBA
+	// pending frame is written when the next changed frame arrives, so unchanged frames can extend its delay
+	pending      *image.Paletted
+	pendingDelay int
+	// last is the last written frame
+	last    *image.Paletted
+	started bool
+	err     error
+}
+
+// NewGIFSink returns a GIF sink writing to w. delay is the frame delay in 100ths of a second,
+// it is clamped to the range 0-65535. opts may be nil for defaults.
+func NewGIFSink(w io.Writer, delay int, opts *GIFOptions) *GIFSink {
+	s := &GIFSink{w: bufio.NewWriter(w), delay: delay}
+	if opts != nil {
+		s.opts = *opts
+	}
+	s.palette = s.opts.Palette
+	return s
+}
+
+// WriteFrame encodes img as the next frame
+func (s *GIFSink) WriteFrame(img image.Image) error {
+	if s.err != nil {
+		return s.err
+	}
+	bounds := img.Bounds()
+	if !s.started {
+		s.size = bounds.Size()
+		if s.palette == nil && !s.opts.PerFramePalette {
+			s.palette = s.opts.quantizer().Quantize(make(color.Palette, 0, s.opts.numColors()), img)
+		}
+		s.err = s.writeHeader()
+		if s.err != nil {
+			return s.err
+		}
+		s.started = true
+	} else if bounds.Size() != s.size {
+		s.err = errFrameSize
+		return s.err
+	}
+
+	r := bounds
+	if s.opts.DeltaFrames {
+		if s.prev != nil {
+			r = changedRect(s.prev, img)
+		}
+		if s.prev == nil {
+			s.prev = image.NewRGBA(bounds)
+		}
+		draw.Draw(s.prev, bounds, img, bounds.Min, draw.Src)
+		if r.Empty() {
+			if s.pendingDelay+s.delay > math.MaxUint16 {
+				// Write the frame with the delay so far and show it longer with a frame that changes nothing
+				if s.err = s.flush(); s.err != nil {
+					return s.err
+				}
+				s.pending = repeatPixel(s.last)
+				s.pendingDelay = 0
+			}
+			s.pendingDelay += s.delay
+			return nil
+		}
+	}
+	palette := s.palette
+	if palette == nil {
+		region := img
+		if si, ok := img.(subImager); ok {
+			region = si.SubImage(r)
+		}
+		palette = s.opts.quantizer().Quantize(make(color.Palette, 0, s.opts.numColors()), region)
+	}
+	if len(palette) == 0 {
+		palette = color.Palette{color.Black}
+	}
+	pm := image.NewPaletted(r.Sub(bounds.Min), palette)
+	s.opts.drawer().Draw(pm, pm.Rect, img, r.Min)
+	if s.err = s.flush(); s.err != nil {
+		return s.err
+	}
+	s.pending = pm
+	s.pendingDelay = s.delay
+	return nil
+}
+
+// Close writes the last frame and the trailer, it does not close the underlying writer
+func (s *GIFSink) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+	if !s.started {
+		return ErrNoFrames
+	}
+	if s.err = s.flush(); s.err != nil {
+		return s.err
+	}
+	if s.err = s.w.WriteByte(0x3b); s.err != nil {
+		return s.err
+	}
+	s.err = s.w.Flush()
+	return s.err
+}
+
+// writeHeader writes the logical screen, the global color table and the loop extension
+func (s *GIFSink) writeHeader() error {
+	if s.opts.LoopCount < -1 || s.opts.LoopCount > math.MaxUint16 {
+		return ErrGIFLoopCount
+	}
+	var lsd [7]byte
+	binary.LittleEndian.PutUint16(lsd[0:], uint16(s.size.X))
+	binary.LittleEndian.PutUint16(lsd[2:], uint16(s.size.Y))
+	if s.palette != nil {
+		lsd[4] = 0x80 | 0x70 | gifTableBits(len(s.palette))
+	}
+	header := append([]byte("GIF89a"), lsd[:]...)
+	if _, err := s.w.Write(header); err != nil {
+		return err
+	}
+	if s.palette != nil {
+		if err := writeGIFColorTable(s.w, s.palette); err != nil {
+			return err
+		}
+	}
+	if s.opts.LoopCount >= 0 {
+		ext := append([]byte{0x21, 0xff, 0x0b}, "NETSCAPE2.0"...)
+		ext = append(ext, 0x03, 0x01, uint8(s.opts.LoopCount), uint8(s.opts.LoopCount>>8), 0x00)
+		if _, err := s.w.Write(ext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush writes the pending frame
+func (s *GIFSink) flush() error {
+	pm := s.pending
+	if pm == nil {
+		return nil
+	}
+	s.pending = nil
+	s.last = pm
+	// Graphic control extension, frames are drawn over the previous ones
+	const disposalNone = 1
+	delay := min(max(s.pendingDelay, 0), math.MaxUint16)
+	if _, err := s.w.Write([]byte{0x21, 0xf9, 0x04, disposalNone << 2,
+		uint8(delay), uint8(delay >> 8), 0x00, 0x00}); err != nil {
+		return err
+	}
+
+	var desc [10]byte
+	desc[0] = 0x2c
+	binary.LittleEndian.PutUint16(desc[1:], uint16(pm.Rect.Min.X))
+	binary.LittleEndian.PutUint16(desc[3:], uint16(pm.Rect.Min.Y))
+	binary.LittleEndian.PutUint16(desc[5:], uint16(pm.Rect.Dx()))
+	binary.LittleEndian.PutUint16(desc[7:], uint16(pm.Rect.Dy()))
+	local := s.palette == nil
+	if local {
+		desc[9] = 0x80 | gifTableBits(len(pm.Palette))
+	}
+	if _, err := s.w.Write(desc[:]); err != nil {
+		return err
+	}
+	if local {
+		if err := writeGIFColorTable(s.w, pm.Palette); err != nil {
+			return err
+		}
+	}
+
+	litWidth := max(2, int(gifTableBits(len(pm.Palette)))+1)
+	if err := s.w.WriteByte(uint8(litWidth)); err != nil {
+		return err
+	}
+	bw := &gifBlockWriter{w: s.w}
+	lw := lzw.NewWriter(bw, lzw.LSB, litWidth)
+	if _, err := lw.Write(pm.Pix); err != nil {
+		return err
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	return bw.close()
+}
+
+// gifTableBits returns the size field of a color table with n colors, the table has 2^(bits+1) entries
+func gifTableBits(n int) uint8 {
+	bits := uint8(0)
+	for 1<<(bits+1) < n {
+		bits++
+	}
+	return bits
+}
+
+func writeGIFColorTable(w *bufio.Writer, p color.Palette) error {
+	size := 1 << (gifTableBits(len(p)) + 1)
+	table := make([]byte, 0, 3*size)
+	for i := range size {
+		var r, g, b uint32
+		if i < len(p) {
+			r, g, b, _ = p[i].RGBA()
+		}
+		table = append(table, uint8(r>>8), uint8(g>>8), uint8(b>>8))
+	}
+	_, err := w.Write(table)
+	return err
+}
+
+// repeatPixel returns a 1x1 frame with the top left pixel of frame, drawing it changes nothing
+func repeatPixel(frame *image.Paletted) *image.Paletted {
+	pt := frame.Rect.Min
+	pm := image.NewPaletted(image.Rectangle{Min: pt, Max: pt.Add(image.Pt(1, 1))}, frame.Palette)
+	pm.Pix[0] = frame.ColorIndexAt(pt.X, pt.Y)
+	return pm
+}
+
+// gifBlockWriter splits image data into sub-blocks of at most 255 bytes
+type gifBlockWriter struct {
+	w   *bufio.Writer
+	buf [255]byte
+	n   int
+}
+
+// Write buffers p. On error it returns the number of bytes of p in the blocks that were written.
+func (b *gifBlockWriter) Write(p []byte) (int, error) {
+	written := 0
+	for i, c := range p {
+		b.buf[b.n] = c
+		b.n++
+		if b.n == len(b.buf) {
+			if err := b.writeBlock(); err != nil {
+				return written, err
+			}
+			written = i + 1
+		}
+	}
+	return len(p), nil
+}
+
+func (b *gifBlockWriter) writeBlock() error {
+	n := b.n
+	b.n = 0
+	if err := b.w.WriteByte(uint8(n)); err != nil {
+		return err
+	}
+	_, err := b.w.Write(b.buf[:n])
+	return err
+}
+
+// close writes the remaining data and the block terminator
+func (b *gifBlockWriter) close() error {
+	if b.n > 0 {
+		if err := b.writeBlock(); err != nil {
+			return err
+		}
+	}
+	return b.w.WriteByte(0)
+}
+
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+// changedRect returns the bounds of the pixels that differ between frames a and b
+func changedRect(a, b image.Image) image.Rectangle {
+	changed := image.Rectangle{}
+	bounds := b.Bounds()
+	ra, okA := a.(*image.RGBA)
+	rb, okB := b.(*image.RGBA)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			same := false
+			if okA && okB {
+				i, j := ra.PixOffset(x, y), rb.PixOffset(x, y)
+				same = [4]uint8(ra.Pix[i:i+4]) == [4]uint8(rb.Pix[j:j+4])
+			} else {
+				same = a.At(x, y) == b.At(x, y)
+			}
+			if !same {
+				changed = changed.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return changed
+}
@@ -3,9 +3,10 @@ module github.com/setanarut/gog/v2
 go 1.24.3
 
 require (
-	github.com/setanarut/apng v1.0.5
 	github.com/setanarut/v v1.1.1
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef
 	github.com/srwiley/scanFT v0.0.0-20220128184157-0d1ee492111f
 	golang.org/x/image v0.27.0
 )
+
+require golang.org/x/text v0.25.0 // indirect
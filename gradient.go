@@ -0,0 +1,243 @@
+package gog
+
+import (
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/setanarut/gog/v2/affine"
+	"github.com/setanarut/gog/v2/utils"
+	"github.com/setanarut/v"
+)
+
+// SpreadMode constants determine how a gradient is painted outside of its 0-1 range
+const (
+	// PadSpread extends the end colors
+	PadSpread SpreadMode = iota
+	// ReflectSpread mirrors the gradient back and forth
+	ReflectSpread
+	// RepeatSpread restarts the gradient
+	RepeatSpread
+)
+
+type SpreadMode uint8
+
+// Pattern is a paint whose color varies over the canvas, such as a gradient.
+//
+// Patterns are also colors, so they can be passed to Context.Fill and set as StrokeStyle.Color.
+type Pattern interface {
+	color.Color
+	// ColorAt returns the color at canvas point x, y
+	ColorAt(x, y float64) color.Color
+}
+
+// ColorStop is a color at an offset along a gradient, offsets are in the range 0-1.
+type ColorStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+// Gradient holds the color stops shared by all gradient types
+type Gradient struct {
+	// Stops in ascending offset order
+	Stops []ColorStop
+	// Spread determines the color outside of the 0-1 range
+	Spread SpreadMode
+	// Transform maps gradient space to canvas space, the zero value is the identity
+	Transform affine.Matrix
+}
+
+// AddStop adds a color stop keeping the stops in ascending offset order
+func (g *Gradient) AddStop(offset float64, c color.Color) *Gradient {
+	i, _ := slices.BinarySearchFunc(g.Stops, offset, func(s ColorStop, o float64) int {
+		if s.Offset <= o {
+			return -1
+		}
+		return 1
+	})
+	g.Stops = slices.Insert(g.Stops, i, ColorStop{Offset: offset, Color: c})
+	return g
+}
+
+// RGBA returns the color of the first stop. It is used where a flat color is required.
+func (g *Gradient) RGBA() (r, gr, b, a uint32) {
+	if len(g.Stops) == 0 {
+		return 0, 0, 0, 0
+	}
+	return g.Stops[0].Color.RGBA()
+}
+
+// colorAtT returns the interpolated color at gradient parameter t
+func (g *Gradient) colorAtT(t float64) color.Color {
+	if len(g.Stops) == 0 {
+		return color.Transparent
+	}
+	switch g.Spread {
+	case RepeatSpread:
+		t -= math.Floor(t)
+	case ReflectSpread:
+		t = math.Mod(math.Abs(t), 2)
+		if t > 1 {
+			t = 2 - t
+		}
+	}
+	first, last := g.Stops[0], g.Stops[len(g.Stops)-1]
+	if t <= first.Offset {
+		return first.Color
+	}
+	if t >= last.Offset {
+		return last.Color
+	}
+	for i := 1; i < len(g.Stops); i++ {
+		s1 := g.Stops[i]
+		if t <= s1.Offset {
+			s0 := g.Stops[i-1]
+			span := s1.Offset - s0.Offset
+			if span <= 0 {
+				return s1.Color
+			}
+			return utils.LerpColor(s0.Color, s1.Color, (t-s0.Offset)/span)
+		}
+	}
+	return last.Color
+}
+
+// matrix returns Transform, or the identity if it is the zero value
+func (g *Gradient) matrix() affine.Matrix {
+	if g.Transform == (affine.Matrix{}) {
+		return affine.Identity
+	}
+	return g.Transform
+}
+
+// sampledPattern is a Pattern that prepares a sampler once per fill
+type sampledPattern interface {
+	Pattern
+	// sampler returns ColorAt with the transform inverted in advance
+	sampler() func(x, y float64) color.Color
+}
+
+// LinearGradient varies color along the line from Start to End
+type LinearGradient struct {
+	Gradient
+	Start, End v.Vec
+}
+
+// NewLinearGradient returns a linear gradient from start to end with identity transform
+func NewLinearGradient(start, end v.Vec, stops ...ColorStop) *LinearGradient {
+	g := &LinearGradient{Start: start, End: end}
+	g.Transform = affine.Identity
+	for _, s := range stops {
+		g.AddStop(s.Offset, s.Color)
+	}
+	return g
+}
+
+// ColorAt returns the color at canvas point x, y
+func (g *LinearGradient) ColorAt(x, y float64) color.Color {
+	return g.sampler()(x, y)
+}
+
+func (g *LinearGradient) sampler() func(x, y float64) color.Color {
+	inv := g.matrix().Invert()
+	d := g.End.Sub(g.Start)
+	l := d.MagSq()
+	return func(x, y float64) color.Color {
+		if l == 0 {
+			return g.colorAtT(0)
+		}
+		return g.colorAtT(inv.Apply(v.Vec{X: x, Y: y}).Sub(g.Start).Dot(d) / l)
+	}
+}
+
+// RadialGradient varies color from Focus (offset 0) to the circle at Center with Radius (offset 1)
+type RadialGradient struct {
+	Gradient
+	Center, Focus v.Vec
+	Radius        float64
+}
+
+// NewRadialGradient returns a radial gradient with the focus at the center and identity transform
+func NewRadialGradient(center v.Vec, radius float64, stops ...ColorStop) *RadialGradient {
+	g := &RadialGradient{Center: center, Focus: center, Radius: radius}
+	g.Transform = affine.Identity
+	for _, s := range stops {
+		g.AddStop(s.Offset, s.Color)
+	}
+	return g
+}
+
+// ColorAt returns the color at canvas point x, y
+func (g *RadialGradient) ColorAt(x, y float64) color.Color {
+	return g.sampler()(x, y)
+}
+
+func (g *RadialGradient) sampler() func(x, y float64) color.Color {
+	inv := g.matrix().Invert()
+	cd := g.Center.Sub(g.Focus)
+	a := cd.Dot(cd) - g.Radius*g.Radius
+	return func(x, y float64) color.Color {
+		if g.Radius <= 0 {
+			return g.colorAtT(1)
+		}
+		// Find the largest t where the point lies on the circle
+		// centered at Focus+(Center-Focus)*t with radius Radius*t
+		pd := inv.Apply(v.Vec{X: x, Y: y}).Sub(g.Focus)
+		b := pd.Dot(cd)
+		c := pd.Dot(pd)
+		var t float64
+		if math.Abs(a) < 1e-9 {
+			if b == 0 {
+				return g.colorAtT(0)
+			}
+			t = c / (2 * b)
+		} else {
+			disc := b*b - a*c
+			if disc < 0 {
+				// Outside of the cone when the focus lies outside of the circle
+				return color.Transparent
+			}
+			sq := math.Sqrt(disc)
+			t = max((b+sq)/a, (b-sq)/a)
+		}
+		// Circles with negative radius are not drawn, like in canvas and SVG
+		if t < 0 {
+			return color.Transparent
+		}
+		return g.colorAtT(t)
+	}
+}
+
+// ConicGradient sweeps color around Center starting at Angle (radians)
+type ConicGradient struct {
+	Gradient
+	Center v.Vec
+	Angle  float64
+}
+
+// NewConicGradient returns a conic (sweep) gradient with identity transform
+func NewConicGradient(center v.Vec, angle float64, stops ...ColorStop) *ConicGradient {
+	g := &ConicGradient{Center: center, Angle: angle}
+	g.Transform = affine.Identity
+	for _, s := range stops {
+		g.AddStop(s.Offset, s.Color)
+	}
+	return g
+}
+
+// ColorAt returns the color at canvas point x, y
+func (g *ConicGradient) ColorAt(x, y float64) color.Color {
+	return g.sampler()(x, y)
+}
+
+func (g *ConicGradient) sampler() func(x, y float64) color.Color {
+	inv := g.matrix().Invert()
+	return func(x, y float64) color.Color {
+		d := inv.Apply(v.Vec{X: x, Y: y}).Sub(g.Center)
+		t := math.Mod(math.Atan2(d.Y, d.X)-g.Angle, 2*math.Pi)
+		if t < 0 {
+			t += 2 * math.Pi
+		}
+		return g.colorAtT(t / (2 * math.Pi))
+	}
+}
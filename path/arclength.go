@@ -0,0 +1,212 @@
+package path
+
+import (
+	"math"
+	"sort"
+
+	"github.com/setanarut/v"
+)
+
+// ArcLength is an arc length parametrization of a Path.
+//
+// The Path is measured once into a table of cumulative lengths, queries use a binary search.
+// Use it instead of Path.PointAngleAtLength when many points are sampled along the same Path,
+// such as a path-follow animation with many movers.
+//
+// The table is rebuilt on the next query after the Path is changed with its methods.
+// Call Invalidate after writing to Path.Points directly.
+// Queries may rebuild the table, so an ArcLength is not safe for concurrent use.
+type ArcLength struct {
+	path *Path
+	rev  uint64
+	// lengths[i] is the arc length from the start to Points[i]
+	lengths []float64
+	// curvatures[i] is the signed curvature at Points[i]
+	curvatures []float64
+	closed     bool
+}
+
+// NewArcLength returns the arc length parametrization of p
+func NewArcLength(p *Path) *ArcLength {
+	a := &ArcLength{path: p}
+	a.build()
+	return a
+}
+
+// ArcLength returns the arc length parametrization of the Path, see NewArcLength
+func (p *Path) ArcLength() *ArcLength {
+	return NewArcLength(p)
+}
+
+// changed invalidates the ArcLength tables of the Path
+func (p *Path) changed() {
+	p.rev++
+}
+
+// Path returns the parametrized Path
+func (a *ArcLength) Path() *Path {
+	return a.path
+}
+
+// Invalidate forces a rebuild of the table on the next query
+func (a *ArcLength) Invalidate() {
+	a.lengths = nil
+}
+
+// Length returns the total length of the Path
+func (a *ArcLength) Length() float64 {
+	a.update()
+	if len(a.lengths) == 0 {
+		return 0
+	}
+	return a.lengths[len(a.lengths)-1]
+}
+
+// LengthAtT returns the arc length at normalized parameter t, 0 is the start and 1 is the end
+func (a *ArcLength) LengthAtT(t float64) float64 {
+	return t * a.Length()
+}
+
+// PointAngleAtLength returns point and tangent angle at length like Path.PointAngleAtLength.
+//
+// Lengths outside of the Path wrap around on closed paths and are clamped on open paths.
+func (a *ArcLength) PointAngleAtLength(length float64) (v.Vec, float64) {
+	i, t, ok := a.locate(length)
+	if !ok {
+		return v.Vec{}, 0
+	}
+	return a.point(i, t), a.tangent(i).Angle()
+}
+
+// PointAt returns the point at length
+func (a *ArcLength) PointAt(length float64) v.Vec {
+	i, t, ok := a.locate(length)
+	if !ok {
+		return v.Vec{}
+	}
+	return a.point(i, t)
+}
+
+// TangentAt returns the unit direction of travel at length
+func (a *ArcLength) TangentAt(length float64) v.Vec {
+	i, _, ok := a.locate(length)
+	if !ok {
+		return v.Vec{}
+	}
+	return a.tangent(i)
+}
+
+// NormalAt returns the unit normal at length, it is the tangent rotated by 90° from +X towards +Y
+func (a *ArcLength) NormalAt(length float64) v.Vec {
+	tangent := a.TangentAt(length)
+	return v.Vec{X: -tangent.Y, Y: tangent.X}
+}
+
+// CurvatureAt returns the signed curvature (1/radius) at length.
+//
+// The curvature of the polyline is estimated at its points from the circle through each point
+// and its neighbours, then interpolated along the segments.
+// It is positive when the Path turns towards NormalAt.
+func (a *ArcLength) CurvatureAt(length float64) float64 {
+	i, t, ok := a.locate(length)
+	if !ok {
+		return 0
+	}
+	return a.curvatures[i] + (a.curvatures[i+1]-a.curvatures[i])*t
+}
+
+// PointAtT returns the point at normalized parameter t
+func (a *ArcLength) PointAtT(t float64) v.Vec {
+	return a.PointAt(a.LengthAtT(t))
+}
+
+// TangentAtT returns the unit direction of travel at normalized parameter t
+func (a *ArcLength) TangentAtT(t float64) v.Vec {
+	return a.TangentAt(a.LengthAtT(t))
+}
+
+// NormalAtT returns the unit normal at normalized parameter t
+func (a *ArcLength) NormalAtT(t float64) v.Vec {
+	return a.NormalAt(a.LengthAtT(t))
+}
+
+// CurvatureAtT returns the signed curvature at normalized parameter t
+func (a *ArcLength) CurvatureAtT(t float64) float64 {
+	return a.CurvatureAt(a.LengthAtT(t))
+}
+
+// update rebuilds the table if the Path has changed
+func (a *ArcLength) update() {
+	if a.lengths == nil || a.rev != a.path.rev {
+		a.build()
+	}
+}
+
+// build measures the Path
+func (a *ArcLength) build() {
+	pts := a.path.Points
+	a.rev = a.path.rev
+	a.closed = len(pts) > 2 && a.path.IsClosed()
+	a.lengths = make([]float64, len(pts))
+	for i := 1; i < len(pts); i++ {
+		a.lengths[i] = a.lengths[i-1] + pts[i-1].Dist(pts[i])
+	}
+	a.curvatures = make([]float64, len(pts))
+	for i := 1; i < len(pts)-1; i++ {
+		a.curvatures[i] = menger(pts[i-1], pts[i], pts[i+1])
+	}
+	if a.closed {
+		k := menger(pts[len(pts)-2], pts[0], pts[1])
+		a.curvatures[0], a.curvatures[len(pts)-1] = k, k
+	}
+}
+
+// locate returns the segment index and the fraction of the segment at length
+func (a *ArcLength) locate(length float64) (int, float64, bool) {
+	a.update()
+	n := len(a.lengths)
+	if n < 2 {
+		return 0, 0, false
+	}
+	total := a.lengths[n-1]
+	if a.closed && total > 0 {
+		length = math.Mod(length, total)
+		if length < 0 {
+			length += total
+		}
+	}
+	length = min(max(length, 0), total)
+	// First point at or after length, the segment before it has a non-zero length
+	i := max(sort.SearchFloat64s(a.lengths, length)-1, 0)
+	for i < n-2 && a.lengths[i+1] == a.lengths[i] {
+		i++
+	}
+	segLength := a.lengths[i+1] - a.lengths[i]
+	if segLength == 0 {
+		return i, 0, true
+	}
+	return i, (length - a.lengths[i]) / segLength, true
+}
+
+// point returns the point at fraction t of segment i
+func (a *ArcLength) point(i int, t float64) v.Vec {
+	return a.path.Points[i].Lerp(a.path.Points[i+1], t)
+}
+
+// tangent returns the unit direction of segment i
+func (a *ArcLength) tangent(i int) v.Vec {
+	d := a.path.Points[i+1].Sub(a.path.Points[i])
+	if mag := d.Mag(); mag > 0 {
+		return d.DivS(mag)
+	}
+	return v.Vec{}
+}
+
+// menger returns the signed curvature of the circle through p0, p1 and p2
+func menger(p0, p1, p2 v.Vec) float64 {
+	d := p0.Dist(p1) * p1.Dist(p2) * p0.Dist(p2)
+	if d == 0 {
+		return 0
+	}
+	return 2 * p1.Sub(p0).Cross(p2.Sub(p1)) / d
+}
@@ -0,0 +1,443 @@
+package path
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// BooleanOp constants determine how Boolean combines two shapes
+const (
+	// UnionOp keeps regions inside either shape
+	UnionOp BooleanOp = iota
+	// IntersectionOp keeps regions inside both shapes
+	IntersectionOp
+	// DifferenceOp keeps regions of the first shape that are outside of the second
+	DifferenceOp
+	// XorOp keeps regions inside exactly one of the shapes
+	XorOp
+)
+
+type BooleanOp uint8
+
+// Union returns the outline of the regions inside a or b, see Boolean
+func Union(a, b Shape) *Compound {
+	return Boolean(UnionOp, a, b)
+}
+
+// Intersection returns the outline of the regions inside both a and b, see Boolean
+func Intersection(a, b Shape) *Compound {
+	return Boolean(IntersectionOp, a, b)
+}
+
+// Difference returns the outline of the regions inside a and outside of b, see Boolean
+func Difference(a, b Shape) *Compound {
+	return Boolean(DifferenceOp, a, b)
+}
+
+// Xor returns the outline of the regions inside exactly one of a and b, see Boolean
+func Xor(a, b Shape) *Compound {
+	return Boolean(XorOp, a, b)
+}
+
+// Boolean combines the filled regions of two shapes and returns the outline of the result.
+//
+// Every subpath is treated as closed, like when it is filled. Inside regions are determined by the
+// FillRule of a Compound, other shapes use NonZero, so self-intersecting and overlapping subpaths
+// and holes are resolved the same way they are drawn. Curves are flattened with DefaultTolerance.
+//
+// The result is a Compound with NonZero fill rule. Its subpaths are closed, outer outlines
+// turn from +X towards +Y the opposite way of holes. Regions that only touch at a point
+// become separate subpaths. Pass nil as b to clean up a single shape.
+func Boolean(op BooleanOp, a, b Shape) *Compound {
+	return combine(op, newRegion(a), newRegion(b))
+}
+
+// combine returns the outline of the result of op on two regions
+func combine(op BooleanOp, ra, rb *region) *Compound {
+	var edges []boolEdge
+	lo, hi := v.Vec{X: math.Inf(1), Y: math.Inf(1)}, v.Vec{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, r := range []*region{ra, rb} {
+		for _, e := range r.edges {
+			edges = append(edges, boolEdge{a: e[0], b: e[1]})
+			lo = v.Vec{X: min(lo.X, e[0].X, e[1].X), Y: min(lo.Y, e[0].Y, e[1].Y)}
+			hi = v.Vec{X: max(hi.X, e[0].X, e[1].X), Y: max(hi.Y, e[0].Y, e[1].Y)}
+		}
+	}
+	if len(edges) == 0 {
+		return NewCompound()
+	}
+	scale := max(1, hi.X-lo.X, hi.Y-lo.Y, math.Abs(lo.X), math.Abs(lo.Y), math.Abs(hi.X), math.Abs(hi.Y))
+	eps := 1e-9 * scale
+	splitEdges(edges, eps)
+
+	// Split edges into pieces between snapped vertices, pieces shared by several edges are kept once
+	snap := newSnapper(eps)
+	var pieces [][2]int
+	seen := make(map[[2]int]bool)
+	for i := range edges {
+		e := &edges[i]
+		slices.SortFunc(e.splits, func(x, y boolSplit) int {
+			return cmp.Compare(x.t, y.t)
+		})
+		chain := []int{snap.id(e.a)}
+		for _, s := range e.splits {
+			chain = append(chain, snap.id(s.pt))
+		}
+		chain = append(chain, snap.id(e.b))
+		for k := 1; k < len(chain); k++ {
+			p, q := chain[k-1], chain[k]
+			if p == q {
+				continue
+			}
+			key := [2]int{min(p, q), max(p, q)}
+			if !seen[key] {
+				seen[key] = true
+				pieces = append(pieces, key)
+			}
+		}
+	}
+	neighbours := make(map[int][]int)
+	for _, pc := range pieces {
+		neighbours[pc[0]] = append(neighbours[pc[0]], pc[1])
+		neighbours[pc[1]] = append(neighbours[pc[1]], pc[0])
+	}
+
+	// Keep the pieces with the result inside on one side only, directed with the inside on the left
+	pts := snap.pts
+	var bounds [][2]int
+	for _, pc := range pieces {
+		pa, pb := pts[pc[0]], pts[pc[1]]
+		d := pb.Sub(pa)
+		length := d.Mag()
+		normal := v.Vec{X: -d.Y / length, Y: d.X / length}
+		// Sample close enough to stay clear of the neighbouring pieces
+		limit := length / 2
+		for _, end := range [2][2]int{{pc[0], pc[1]}, {pc[1], pc[0]}} {
+			dir := pts[end[1]].Sub(pts[end[0]]).DivS(length)
+			for _, n := range neighbours[end[0]] {
+				if n == end[1] {
+					continue
+				}
+				other := pts[n].Sub(pts[end[0]])
+				other = other.DivS(other.Mag())
+				if sin := math.Abs(dir.Cross(other)); sin > 1e-9 && dir.Dot(other) > 0 {
+					limit = min(limit, length/2*sin)
+				}
+			}
+		}
+		delta := min(1e-6*scale, limit/2)
+		mid := pa.Lerp(pb, 0.5)
+		left, right := mid.Add(normal.Scale(delta)), mid.Sub(normal.Scale(delta))
+		inLeft := op.apply(ra.contains(left), rb.contains(left))
+		inRight := op.apply(ra.contains(right), rb.contains(right))
+		switch {
+		case inLeft && !inRight:
+			bounds = append(bounds, pc)
+		case inRight && !inLeft:
+			bounds = append(bounds, [2]int{pc[1], pc[0]})
+		}
+	}
+	return NewCompound(linkRings(bounds, pts, eps)...)
+}
+
+// apply returns whether a point inside a and b is inside the result
+func (op BooleanOp) apply(a, b bool) bool {
+	switch op {
+	case IntersectionOp:
+		return a && b
+	case DifferenceOp:
+		return a && !b
+	case XorOp:
+		return a != b
+	}
+	return a || b
+}
+
+// region is a flattened shape with its fill rule
+type region struct {
+	// edges holds directed edges, closed subpaths include their closing edge
+	edges [][2]v.Vec
+	rule  FillRule
+	ring  []v.Vec
+	// rows buckets edges by y to speed up contains
+	rows           [][]int
+	top, rowHeight float64
+}
+
+func newRegion(s Shape) *region {
+	r := &region{}
+	if s == nil {
+		return r
+	}
+	if c, ok := s.(*Compound); ok {
+		r.rule = c.FillRule
+	}
+	s.Trace(r)
+	return r
+}
+
+// Start starts a new ring at pt
+func (r *region) Start(pt v.Vec) {
+	r.ring = []v.Vec{pt}
+}
+
+// Line adds a line to pt
+func (r *region) Line(pt v.Vec) {
+	if pt != r.ring[len(r.ring)-1] {
+		r.ring = append(r.ring, pt)
+	}
+}
+
+// QuadBezier adds a flattened quadratic bezier
+func (r *region) QuadBezier(ctrl, pt v.Vec) {
+	seg := Segment{Kind: QuadSegment, Ctrl1: ctrl, End: pt}
+	r.ring = seg.flatten(r.ring, r.ring[len(r.ring)-1], DefaultTolerance)
+}
+
+// CubeBezier adds a flattened cubic bezier
+func (r *region) CubeBezier(ctrl1, ctrl2, pt v.Vec) {
+	seg := Segment{Kind: CubicSegment, Ctrl1: ctrl1, Ctrl2: ctrl2, End: pt}
+	r.ring = seg.flatten(r.ring, r.ring[len(r.ring)-1], DefaultTolerance)
+}
+
+// Stop ends the ring, rings are always closed
+func (r *region) Stop(bool) {
+	ring := r.ring
+	for len(ring) > 1 && ring[len(ring)-1] == ring[0] {
+		ring = ring[:len(ring)-1]
+	}
+	if len(ring) > 2 {
+		for i, pt := range ring {
+			r.addEdge(pt, ring[(i+1)%len(ring)])
+		}
+	}
+	r.ring = nil
+}
+
+// addEdge adds a directed edge, zero length edges are skipped
+func (r *region) addEdge(a, b v.Vec) {
+	if a != b {
+		r.edges = append(r.edges, [2]v.Vec{a, b})
+		r.rows = nil
+	}
+}
+
+// contains reports whether pt is inside the region
+func (r *region) contains(pt v.Vec) bool {
+	if len(r.edges) == 0 {
+		return false
+	}
+	if r.rows == nil {
+		r.index()
+	}
+	row := int((pt.Y - r.top) / r.rowHeight)
+	if row < 0 || row >= len(r.rows) {
+		return false
+	}
+	w := 0
+	for _, i := range r.rows[row] {
+		w += crossing(r.edges[i][0], r.edges[i][1], pt)
+	}
+	return r.rule.inside(w)
+}
+
+// index buckets the edges into rows of equal height
+func (r *region) index() {
+	top, bottom := math.Inf(1), math.Inf(-1)
+	for _, e := range r.edges {
+		top = min(top, e[0].Y, e[1].Y)
+		bottom = max(bottom, e[0].Y, e[1].Y)
+	}
+	n := max(1, int(math.Sqrt(float64(len(r.edges)))))
+	r.top = top
+	r.rowHeight = max((bottom-top)/float64(n), 1e-12)
+	r.rows = make([][]int, n+1)
+	for i, e := range r.edges {
+		first := int((min(e[0].Y, e[1].Y) - top) / r.rowHeight)
+		last := min(int((max(e[0].Y, e[1].Y)-top)/r.rowHeight), n)
+		for row := first; row <= last; row++ {
+			r.rows[row] = append(r.rows[row], i)
+		}
+	}
+}
+
+// crossing returns the winding number contribution of edge a-b around pt
+func crossing(a, b, pt v.Vec) int {
+	if a.Y <= pt.Y {
+		if b.Y > pt.Y && b.Sub(a).Cross(pt.Sub(a)) > 0 {
+			return 1
+		}
+	} else if b.Y <= pt.Y && b.Sub(a).Cross(pt.Sub(a)) < 0 {
+		return -1
+	}
+	return 0
+}
+
+// boolEdge is an input edge with the points where other edges cross or touch it
+type boolEdge struct {
+	a, b   v.Vec
+	splits []boolSplit
+}
+
+type boolSplit struct {
+	t  float64
+	pt v.Vec
+}
+
+// splitEdges finds the crossings, touching points and overlaps of all edges
+func splitEdges(edges []boolEdge, eps float64) {
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int {
+		return cmp.Compare(min(edges[i].a.X, edges[i].b.X), min(edges[j].a.X, edges[j].b.X))
+	})
+	for k, i := range order {
+		e := &edges[i]
+		eMaxX := max(e.a.X, e.b.X) + eps
+		eMinY, eMaxY := min(e.a.Y, e.b.Y)-eps, max(e.a.Y, e.b.Y)+eps
+		for _, j := range order[k+1:] {
+			f := &edges[j]
+			if min(f.a.X, f.b.X) > eMaxX {
+				break
+			}
+			if max(f.a.Y, f.b.Y) < eMinY || min(f.a.Y, f.b.Y) > eMaxY {
+				continue
+			}
+			e.splitAtPoint(f.a, eps)
+			e.splitAtPoint(f.b, eps)
+			f.splitAtPoint(e.a, eps)
+			f.splitAtPoint(e.b, eps)
+			d1, d2 := e.b.Sub(e.a), f.b.Sub(f.a)
+			den := d1.Cross(d2)
+			if math.Abs(den) <= 1e-12*d1.Mag()*d2.Mag() {
+				continue
+			}
+			w := f.a.Sub(e.a)
+			t, u := w.Cross(d2)/den, w.Cross(d1)/den
+			if t > 0 && t < 1 && u > 0 && u < 1 {
+				pt := e.a.Lerp(e.b, t)
+				e.splits = append(e.splits, boolSplit{t, pt})
+				f.splits = append(f.splits, boolSplit{u, pt})
+			}
+		}
+	}
+}
+
+// splitAtPoint splits the edge at pt if pt lies inside of it
+func (e *boolEdge) splitAtPoint(pt v.Vec, eps float64) {
+	d := e.b.Sub(e.a)
+	l := d.MagSq()
+	if l == 0 {
+		return
+	}
+	t := pt.Sub(e.a).Dot(d) / l
+	if t <= 0 || t >= 1 || e.a.Lerp(e.b, t).Dist(pt) > eps {
+		return
+	}
+	if pt.Dist(e.a) <= eps || pt.Dist(e.b) <= eps {
+		return
+	}
+	e.splits = append(e.splits, boolSplit{t, pt})
+}
+
+// snapper merges points closer than eps into one vertex
+type snapper struct {
+	eps   float64
+	cells map[[2]int64][]int
+	pts   []v.Vec
+}
+
+func newSnapper(eps float64) *snapper {
+	return &snapper{eps: eps, cells: make(map[[2]int64][]int)}
+}
+
+// id returns the vertex index of pt
+func (s *snapper) id(pt v.Vec) int {
+	cx, cy := int64(math.Floor(pt.X/s.eps)), int64(math.Floor(pt.Y/s.eps))
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, i := range s.cells[[2]int64{cx + dx, cy + dy}] {
+				if s.pts[i].Dist(pt) <= s.eps {
+					return i
+				}
+			}
+		}
+	}
+	s.pts = append(s.pts, pt)
+	cell := [2]int64{cx, cy}
+	s.cells[cell] = append(s.cells[cell], len(s.pts)-1)
+	return len(s.pts) - 1
+}
+
+// linkRings joins directed edges into closed paths, taking the sharpest left turn at shared vertices.
+// Chains that end before returning to their start come from unmatched edges and are dropped.
+func linkRings(edges [][2]int, pts []v.Vec, eps float64) []*Path {
+	out := make(map[int][]int)
+	for i, e := range edges {
+		out[e[0]] = append(out[e[0]], i)
+	}
+	used := make([]bool, len(edges))
+	var paths []*Path
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+		first := edges[start][0]
+		ring := []v.Vec{pts[first]}
+		cur := start
+		closed := true
+		for edges[cur][1] != first {
+			from, to := edges[cur][0], edges[cur][1]
+			ring = append(ring, pts[to])
+			din := pts[to].Sub(pts[from])
+			next, best := -1, math.Inf(-1)
+			for _, k := range out[to] {
+				if used[k] {
+					continue
+				}
+				dout := pts[edges[k][1]].Sub(pts[to])
+				if turn := math.Atan2(din.Cross(dout), din.Dot(dout)); turn > best {
+					next, best = k, turn
+				}
+			}
+			if next < 0 {
+				closed = false
+				break
+			}
+			used[next] = true
+			cur = next
+		}
+		if !closed {
+			continue
+		}
+		ring = removeCollinear(ring, eps)
+		if len(ring) > 2 {
+			paths = append(paths, NewPath(append(ring, ring[0])))
+		}
+	}
+	return paths
+}
+
+// removeCollinear removes points of the closed ring that lie on the line of their neighbours
+func removeCollinear(ring []v.Vec, eps float64) []v.Vec {
+	for removed := true; removed && len(ring) > 2; {
+		removed = false
+		for i := 0; i < len(ring) && len(ring) > 2; i++ {
+			prev, pt, next := ring[(i+len(ring)-1)%len(ring)], ring[i], ring[(i+1)%len(ring)]
+			d := next.Sub(prev)
+			l := d.Mag()
+			if l > 0 && math.Abs(d.Cross(pt.Sub(prev)))/l <= eps && pt.Sub(prev).Dot(next.Sub(pt)) > 0 {
+				ring = slices.Delete(ring, i, i+1)
+				i--
+				removed = true
+			}
+		}
+	}
+	return ring
+}
@@ -0,0 +1,161 @@
+package path
+
+import (
+	"github.com/setanarut/gog/v2/affine"
+	"github.com/setanarut/gog/v2/utils"
+	"github.com/setanarut/v"
+)
+
+// Compound is a shape made of several subpaths, such as a donut or a letter with counters.
+//
+// All subpaths are filled together in one pass, so overlapping regions are resolved by FillRule
+// instead of being painted twice.
+type Compound struct {
+	// Anchor point
+	Anchor v.Vec
+	// Paths holds the subpaths
+	Paths []*Path
+	// FillRule determines which regions are inside, use EvenOdd to cut holes regardless of subpath direction
+	FillRule FillRule
+}
+
+// NewCompound returns new Compound from subpaths with NonZero fill rule
+func NewCompound(paths ...*Path) *Compound {
+	c := &Compound{Paths: paths}
+	if len(paths) > 0 {
+		c.SetAnchorToCentroid()
+	}
+	return c
+}
+
+// SetFillRule sets fill rule of the Compound
+func (c *Compound) SetFillRule(rule FillRule) *Compound {
+	c.FillRule = rule
+	return c
+}
+
+// AppendPaths appends subpaths to the Compound
+func (c *Compound) AppendPaths(paths ...*Path) *Compound {
+	c.Paths = append(c.Paths, paths...)
+	return c
+}
+
+// Trace sends the outlines of all subpaths to pen
+func (c *Compound) Trace(pen Pen) {
+	for _, p := range c.Paths {
+		p.Trace(pen)
+	}
+}
+
+// Len returns number of subpaths
+func (c *Compound) Len() int {
+	return len(c.Paths)
+}
+
+// Centroid returns the average of the subpath centroids.
+func (c *Compound) Centroid() v.Vec {
+	centroidPoint := v.Vec{}
+	n := 0
+	for _, p := range c.Paths {
+		if p.Len() > 0 {
+			centroidPoint = centroidPoint.Add(p.Centroid())
+			n++
+		}
+	}
+	if n == 0 {
+		return centroidPoint
+	}
+	return centroidPoint.DivS(float64(n))
+}
+
+// SetAnchor Sets Compound's anchor point
+func (c *Compound) SetAnchor(pt v.Vec) *Compound {
+	c.Anchor = pt
+	return c
+}
+
+// SetAnchorToCentroid Sets Compound's anchor point to centroid
+func (c *Compound) SetAnchorToCentroid() *Compound {
+	return c.SetAnchor(c.Centroid())
+}
+
+// Bounds returns bounds min/max of all subpaths
+func (c *Compound) Bounds() (v.Vec, v.Vec) {
+	var lo, hi v.Vec
+	first := true
+	for _, p := range c.Paths {
+		if p.Len() == 0 {
+			continue
+		}
+		a, b := p.Bounds()
+		if first {
+			lo, hi = a, b
+			first = false
+			continue
+		}
+		lo = v.Vec{X: min(lo.X, a.X), Y: min(lo.Y, a.Y)}
+		hi = v.Vec{X: max(hi.X, b.X), Y: max(hi.Y, b.Y)}
+	}
+	return lo, hi
+}
+
+// SetPos Aligns the Compound with the anchor point to the desired point.
+func (c *Compound) SetPos(position v.Vec) *Compound {
+	return c.Translate(position.X-c.Anchor.X, position.Y-c.Anchor.Y)
+}
+
+// Translate translates all subpaths
+func (c *Compound) Translate(x, y float64) *Compound {
+	for _, p := range c.Paths {
+		p.Translate(x, y)
+	}
+	c.Anchor = c.Anchor.Add(v.Vec{X: x, Y: y})
+	return c
+}
+
+// Rotate rotates all subpaths about Compound.Anchor point
+func (c *Compound) Rotate(angle float64) *Compound {
+	for _, p := range c.Paths {
+		p.Anchor = utils.RotateAbout(p.Anchor, angle, c.Anchor)
+		for i := range p.Points {
+			p.Points[i] = utils.RotateAbout(p.Points[i], angle, c.Anchor)
+		}
+		p.changed()
+	}
+	return c
+}
+
+// Rotated returns new rotated Compound about Compound.Anchor point
+func (c *Compound) Rotated(angle float64) *Compound {
+	return c.Clone().Rotate(angle)
+}
+
+// Scale scales all subpaths at the Compound.Anchor point.
+func (c *Compound) Scale(factor v.Vec) *Compound {
+	for _, p := range c.Paths {
+		p.Anchor = factor.Mul(p.Anchor.Sub(c.Anchor)).Add(c.Anchor)
+		for i := range p.Points {
+			p.Points[i] = factor.Mul(p.Points[i].Sub(c.Anchor)).Add(c.Anchor)
+		}
+		p.changed()
+	}
+	return c
+}
+
+// Transform applies the affine matrix to all subpaths and the anchor
+func (c *Compound) Transform(m affine.Matrix) *Compound {
+	for _, p := range c.Paths {
+		p.Transform(m)
+	}
+	c.Anchor = m.Apply(c.Anchor)
+	return c
+}
+
+// Clone returns deep copy of the Compound
+func (c *Compound) Clone() *Compound {
+	paths := make([]*Path, len(c.Paths))
+	for i, p := range c.Paths {
+		paths[i] = p.Clone()
+	}
+	return &Compound{Anchor: c.Anchor, Paths: paths, FillRule: c.FillRule}
+}
@@ -0,0 +1,267 @@
+package path
+
+import (
+	"math"
+
+	"github.com/setanarut/gog/v2/affine"
+	"github.com/setanarut/gog/v2/utils"
+	"github.com/setanarut/v"
+)
+
+// DefaultTolerance is the flattening tolerance used when a Curve is measured as a polyline,
+// such as in Bounds and Centroid.
+const DefaultTolerance = 0.1
+
+// Curve is a path made of line, quadratic, cubic and elliptical arc segments.
+//
+// Unlike Path, curves are not flattened. They are drawn as true curves and stay smooth when scaled up.
+// Use Flatten to get a polyline Path when one is needed.
+type Curve struct {
+	// Anchor point
+	Anchor v.Vec
+	// Segments holds the pieces of the curve in drawing order
+	Segments []Segment
+	// Closed curves return to their start point with a line
+	Closed bool
+
+	start v.Vec
+}
+
+// NewCurve returns new Curve starting at start, the anchor is also set to start.
+//
+// Build the curve with LineTo, QuadTo, CubeTo and ArcTo, then call SetAnchorToCentroid if needed.
+func NewCurve(start v.Vec) *Curve {
+	return &Curve{Anchor: start, start: start}
+}
+
+// Start returns start point of the Curve
+func (c *Curve) Start() v.Vec {
+	return c.start
+}
+
+// End returns end point of the Curve
+func (c *Curve) End() v.Vec {
+	if len(c.Segments) == 0 {
+		return c.start
+	}
+	return c.Segments[len(c.Segments)-1].End
+}
+
+// LineTo appends a line segment to pt
+func (c *Curve) LineTo(pt v.Vec) *Curve {
+	c.Segments = append(c.Segments, Segment{Kind: LineSegment, End: pt})
+	return c
+}
+
+// QuadTo appends a quadratic bezier segment with control point ctrl
+func (c *Curve) QuadTo(ctrl, pt v.Vec) *Curve {
+	c.Segments = append(c.Segments, Segment{Kind: QuadSegment, Ctrl1: ctrl, End: pt})
+	return c
+}
+
+// CubeTo appends a cubic bezier segment with control points ctrl1 and ctrl2
+func (c *Curve) CubeTo(ctrl1, ctrl2, pt v.Vec) *Curve {
+	c.Segments = append(c.Segments, Segment{Kind: CubicSegment, Ctrl1: ctrl1, Ctrl2: ctrl2, End: pt})
+	return c
+}
+
+// ArcTo appends an elliptical arc around center that starts at the current end point.
+//
+// rotation is the angle of the ellipse x axis and sweep is the signed angle to travel, in radians.
+// Positive sweep turns from +X towards +Y. Use equal radii for a circular arc.
+// If the end point is not on the ellipse, a line to the arc start is added.
+// Zero radii are ignored.
+func (c *Curve) ArcTo(center v.Vec, radiusX, radiusY, rotation, sweep float64) *Curve {
+	if radiusX == 0 || radiusY == 0 {
+		return c
+	}
+	sin, cos := math.Sincos(rotation)
+	axisX := v.Vec{X: cos * radiusX, Y: sin * radiusX}
+	axisY := v.Vec{X: -sin * radiusY, Y: cos * radiusY}
+	// Angle of the current point in the unit circle space of the ellipse
+	d := utils.RotateAbout(c.End(), -rotation, center).Sub(center)
+	startAngle := math.Atan2(d.Y/radiusY, d.X/radiusX)
+	seg := Segment{
+		Kind:       ArcSegment,
+		Center:     center,
+		AxisX:      axisX,
+		AxisY:      axisY,
+		StartAngle: startAngle,
+		Sweep:      sweep,
+	}
+	if arcStart := seg.pointAt(v.Vec{}, 0); arcStart.Dist(c.End()) > 1e-9 {
+		c.LineTo(arcStart)
+	}
+	seg.End = seg.pointAt(v.Vec{}, 1)
+	c.Segments = append(c.Segments, seg)
+	return c
+}
+
+// Close closes the Curve with a line back to its start point
+func (c *Curve) Close() *Curve {
+	c.Closed = true
+	return c
+}
+
+// Open opens the Curve
+func (c *Curve) Open() *Curve {
+	c.Closed = false
+	return c
+}
+
+// IsClosed returns true if Curve closed
+func (c *Curve) IsClosed() bool {
+	return c.Closed
+}
+
+// Trace sends the outline of the Curve to pen, curves are not flattened
+func (c *Curve) Trace(pen Pen) {
+	pen.Start(c.start)
+	for i := range c.Segments {
+		c.Segments[i].trace(pen)
+	}
+	pen.Stop(c.Closed)
+}
+
+// Flatten returns a polyline Path that deviates at most tolerance from the Curve.
+//
+// Flat parts get few points and tight bends get many.
+func (c *Curve) Flatten(tolerance float64) *Path {
+	pts := []v.Vec{c.start}
+	prev := c.start
+	for i := range c.Segments {
+		pts = c.Segments[i].flatten(pts, prev, tolerance)
+		prev = c.Segments[i].End
+	}
+	p := &Path{Points: pts, Anchor: c.Anchor}
+	if c.Closed {
+		p.Close()
+	}
+	return p
+}
+
+// Length returns the arc length of the Curve, curved segments are integrated numerically
+func (c *Curve) Length() float64 {
+	length := 0.0
+	c.eachSegment(func(seg *Segment, start v.Vec) bool {
+		length += seg.length(start, 0, 1)
+		return true
+	})
+	return length
+}
+
+// PointAngleAtLength Returns point and tangent angle at arc length
+func (c *Curve) PointAngleAtLength(length float64) (v.Vec, float64) {
+	var pt v.Vec
+	var ang float64
+	traveledDist := 0.0
+	found := false
+	c.eachSegment(func(seg *Segment, start v.Vec) bool {
+		segmentLength := seg.length(start, 0, 1)
+		if traveledDist+segmentLength >= length {
+			t := seg.paramAtLength(start, length-traveledDist, segmentLength)
+			pt = seg.pointAt(start, t)
+			ang = seg.tangentAt(start, t).Angle()
+			found = true
+			return false
+		}
+		traveledDist += segmentLength
+		return true
+	})
+	if !found {
+		return v.Vec{}, 0.0
+	}
+	return pt, ang
+}
+
+// eachSegment calls fn with every segment and its start point, including the closing line
+func (c *Curve) eachSegment(fn func(seg *Segment, start v.Vec) bool) {
+	prev := c.start
+	for i := range c.Segments {
+		if !fn(&c.Segments[i], prev) {
+			return
+		}
+		prev = c.Segments[i].End
+	}
+	if c.Closed && prev != c.start {
+		fn(&Segment{Kind: LineSegment, End: c.start}, prev)
+	}
+}
+
+// Bounds returns bounds min/max of the flattened Curve
+func (c *Curve) Bounds() (v.Vec, v.Vec) {
+	return c.Flatten(DefaultTolerance).Bounds()
+}
+
+// Centroid returns the centroid of the flattened Curve
+func (c *Curve) Centroid() v.Vec {
+	return c.Flatten(DefaultTolerance).Centroid()
+}
+
+// SetAnchor Sets Curve's anchor point
+func (c *Curve) SetAnchor(pt v.Vec) *Curve {
+	c.Anchor = pt
+	return c
+}
+
+// SetAnchorToCentroid Sets Curve's anchor point to centroid
+func (c *Curve) SetAnchorToCentroid() *Curve {
+	return c.SetAnchor(c.Centroid())
+}
+
+// SetPos Aligns the Curve with the anchor point to the desired point.
+func (c *Curve) SetPos(position v.Vec) *Curve {
+	return c.Translate(position.X-c.Anchor.X, position.Y-c.Anchor.Y)
+}
+
+// Translate translates the Curve
+func (c *Curve) Translate(x, y float64) *Curve {
+	q := v.Vec{X: x, Y: y}
+	c.mapPoints(func(p v.Vec) v.Vec { return p.Add(q) }, func(d v.Vec) v.Vec { return d })
+	c.Anchor = c.Anchor.Add(q)
+	return c
+}
+
+// Rotate rotates the Curve about Curve.Anchor point
+func (c *Curve) Rotate(angle float64) *Curve {
+	c.mapPoints(
+		func(p v.Vec) v.Vec { return utils.RotateAbout(p, angle, c.Anchor) },
+		func(d v.Vec) v.Vec { return d.Rotate(angle) })
+	return c
+}
+
+// Rotated returns new rotated Curve about Curve.Anchor point
+func (c *Curve) Rotated(angle float64) *Curve {
+	return c.Clone().Rotate(angle)
+}
+
+// Scale scales the Curve at the Anchor point. Curves stay exact at any scale.
+func (c *Curve) Scale(factor v.Vec) *Curve {
+	c.mapPoints(
+		func(p v.Vec) v.Vec { return factor.Mul(p.Sub(c.Anchor)).Add(c.Anchor) },
+		func(d v.Vec) v.Vec { return factor.Mul(d) })
+	return c
+}
+
+// Transform applies the affine matrix to the Curve and its anchor, arcs stay exact
+func (c *Curve) Transform(m affine.Matrix) *Curve {
+	c.mapPoints(m.Apply, m.ApplyVector)
+	c.Anchor = m.Apply(c.Anchor)
+	return c
+}
+
+// mapPoints maps all points with point and all arc axis vectors with vec
+func (c *Curve) mapPoints(point, vec func(v.Vec) v.Vec) {
+	c.start = point(c.start)
+	for i := range c.Segments {
+		c.Segments[i].transform(point, vec)
+	}
+}
+
+// Clone returns copy of the Curve
+func (c *Curve) Clone() *Curve {
+	clone := *c
+	clone.Segments = make([]Segment, len(c.Segments))
+	copy(clone.Segments, c.Segments)
+	return &clone
+}
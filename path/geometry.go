@@ -0,0 +1,202 @@
+package path
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Moments holds the area properties of a closed shape
+type Moments struct {
+	// Area is the unsigned area
+	Area float64
+	// Centroid is the center of the area
+	Centroid v.Vec
+	// Second moments of area about the centroid, Ixx is ∫y², Iyy is ∫x² and Ixy is ∫xy
+	Ixx, Iyy, Ixy float64
+}
+
+// SignedArea returns the area of the Path, positive when it turns from +X towards +Y
+// (clockwise on the canvas). Open paths are treated as closed, like when they are filled.
+func (p *Path) SignedArea() float64 {
+	area := 0.0
+	p.eachEdge(func(a, b v.Vec) {
+		area += a.Cross(b)
+	})
+	return area / 2
+}
+
+// Area returns the unsigned area of the Path
+func (p *Path) Area() float64 {
+	return math.Abs(p.SignedArea())
+}
+
+// IsClockwise returns true if the Path turns clockwise on the canvas, where +Y points down
+func (p *Path) IsClockwise() bool {
+	return p.SignedArea() > 0
+}
+
+// AreaCentroid returns the center of the area of the Path.
+//
+// Unlike Centroid, it does not depend on how densely the outline is sampled.
+// Centroid is returned for paths without area.
+func (p *Path) AreaCentroid() v.Vec {
+	m := p.Moments()
+	if m.Area == 0 {
+		return p.Centroid()
+	}
+	return m.Centroid
+}
+
+// Moments returns the area, centroid and second moments of area of the Path
+func (p *Path) Moments() Moments {
+	var s momentSums
+	p.eachEdge(s.add)
+	return s.moments()
+}
+
+// WindingNumber returns how many times the Path winds around pt, positive for clockwise turns
+func (p *Path) WindingNumber(pt v.Vec) int {
+	w := 0
+	p.eachEdge(func(a, b v.Vec) {
+		w += crossing(a, b, pt)
+	})
+	return w
+}
+
+// Contains reports whether pt is inside the filled Path
+func (p *Path) Contains(pt v.Vec, rule FillRule) bool {
+	return rule.inside(p.WindingNumber(pt))
+}
+
+// ClosestPoint returns the point of the Path nearest to pt and its arc length from the start
+func (p *Path) ClosestPoint(pt v.Vec) (v.Vec, float64) {
+	if len(p.Points) == 0 {
+		return v.Vec{}, 0
+	}
+	closest, length := p.Points[0], 0.0
+	best := pt.DistSq(closest)
+	traveled := 0.0
+	for i := range len(p.Points) - 1 {
+		a, b := p.Points[i], p.Points[i+1]
+		d := b.Sub(a)
+		segLength := d.Mag()
+		t := 0.0
+		if segLength > 0 {
+			t = min(max(pt.Sub(a).Dot(d)/(segLength*segLength), 0), 1)
+		}
+		q := a.Add(d.Scale(t))
+		if dist := pt.DistSq(q); dist < best {
+			closest, length, best = q, traveled+t*segLength, dist
+		}
+		traveled += segLength
+	}
+	return closest, length
+}
+
+// Distance returns the distance from pt to the nearest point of the Path
+func (p *Path) Distance(pt v.Vec) float64 {
+	closest, _ := p.ClosestPoint(pt)
+	return pt.Dist(closest)
+}
+
+// eachEdge calls fn with every edge of the Path including the closing edge
+func (p *Path) eachEdge(fn func(a, b v.Vec)) {
+	n := len(p.Points)
+	if n < 2 {
+		return
+	}
+	for i := range n - 1 {
+		fn(p.Points[i], p.Points[i+1])
+	}
+	if p.Points[n-1] != p.Points[0] {
+		fn(p.Points[n-1], p.Points[0])
+	}
+}
+
+// SignedArea returns the sum of the signed areas of all subpaths
+func (c *Compound) SignedArea() float64 {
+	area := 0.0
+	for _, p := range c.Paths {
+		area += p.SignedArea()
+	}
+	return area
+}
+
+// Area returns the unsigned area of the Compound.
+//
+// Subpath areas are added with their signs, so holes must turn the opposite way of their outlines,
+// as in the results of Boolean.
+func (c *Compound) Area() float64 {
+	return math.Abs(c.SignedArea())
+}
+
+// AreaCentroid returns the center of the area of the Compound, holes are subtracted like in Area
+func (c *Compound) AreaCentroid() v.Vec {
+	m := c.Moments()
+	if m.Area == 0 {
+		return c.Centroid()
+	}
+	return m.Centroid
+}
+
+// Moments returns the area, centroid and second moments of area of the Compound, see Area
+func (c *Compound) Moments() Moments {
+	var s momentSums
+	for _, p := range c.Paths {
+		p.eachEdge(s.add)
+	}
+	return s.moments()
+}
+
+// WindingNumber returns the sum of the winding numbers of all subpaths around pt
+func (c *Compound) WindingNumber(pt v.Vec) int {
+	w := 0
+	for _, p := range c.Paths {
+		w += p.WindingNumber(pt)
+	}
+	return w
+}
+
+// Contains reports whether pt is inside the filled Compound using its FillRule
+func (c *Compound) Contains(pt v.Vec) bool {
+	return c.FillRule.inside(c.WindingNumber(pt))
+}
+
+// inside reports whether a winding number is inside
+func (rule FillRule) inside(winding int) bool {
+	if rule == EvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
+
+// momentSums accumulates the polygon area integrals of edges
+type momentSums struct {
+	area, cx, cy, xx, yy, xy float64
+}
+
+func (s *momentSums) add(a, b v.Vec) {
+	c := a.Cross(b)
+	s.area += c
+	s.cx += (a.X + b.X) * c
+	s.cy += (a.Y + b.Y) * c
+	s.xx += (a.X*a.X + a.X*b.X + b.X*b.X) * c
+	s.yy += (a.Y*a.Y + a.Y*b.Y + b.Y*b.Y) * c
+	s.xy += (a.X*b.Y + 2*a.X*a.Y + 2*b.X*b.Y + b.X*a.Y) * c
+}
+
+// moments converts the sums to Moments about the centroid
+func (s *momentSums) moments() Moments {
+	area := s.area / 2
+	if area == 0 {
+		return Moments{}
+	}
+	centroid := v.Vec{X: s.cx / (6 * area), Y: s.cy / (6 * area)}
+	// Integrals about the origin, then moved to the centroid
+	sign := math.Copysign(1, area)
+	iyy := sign * (s.xx/12 - area*centroid.X*centroid.X)
+	ixx := sign * (s.yy/12 - area*centroid.Y*centroid.Y)
+	ixy := sign * (s.xy/24 - area*centroid.X*centroid.Y)
+	return Moments{Area: math.Abs(area), Centroid: centroid, Ixx: ixx, Iyy: iyy, Ixy: ixy}
+}
@@ -0,0 +1,240 @@
+package path
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// OrientedBox is a rotated rectangle
+type OrientedBox struct {
+	Center v.Vec
+	// Size is the width along Angle and the height across it
+	Size v.Vec
+	// Angle of the width side in radians
+	Angle float64
+}
+
+// Corners returns the corners of the box, turning from +X towards +Y like shapes.Rect
+func (b OrientedBox) Corners() [4]v.Vec {
+	u := v.Vec{X: math.Cos(b.Angle), Y: math.Sin(b.Angle)}
+	n := v.Vec{X: -u.Y, Y: u.X}
+	hu, hn := u.Scale(b.Size.X/2), n.Scale(b.Size.Y/2)
+	return [4]v.Vec{
+		b.Center.Sub(hu).Sub(hn),
+		b.Center.Add(hu).Sub(hn),
+		b.Center.Add(hu).Add(hn),
+		b.Center.Sub(hu).Add(hn),
+	}
+}
+
+// Path returns the box as a closed Path anchored at its center
+func (b OrientedBox) Path() *Path {
+	c := b.Corners()
+	return &Path{Points: []v.Vec{c[0], c[1], c[2], c[3], c[0]}, Anchor: b.Center}
+}
+
+// Area returns the area of the box
+func (b OrientedBox) Area() float64 {
+	return b.Size.X * b.Size.Y
+}
+
+// ConvexHull returns the convex hull of the points as a new closed Path, turning from +X towards +Y.
+//
+// Collinear points on the hull edges are left out. Fewer than 3 distinct points, or points on a
+// line, give an open Path of the extreme points. The Path is not changed.
+func (p *Path) ConvexHull() *Path {
+	return &Path{Points: closeHull(convexHull(p.Points)), Anchor: p.Anchor}
+}
+
+// MinEnclosingCircle returns the center and radius of the smallest circle that contains all points
+func (p *Path) MinEnclosingCircle() (v.Vec, float64) {
+	return enclosingCircle(convexHull(p.Points))
+}
+
+// OrientedBounds returns the smallest area rectangle that contains all points, in any rotation.
+//
+// One side of the box lies on an edge of the convex hull. Bounds is the axis-aligned box.
+func (p *Path) OrientedBounds() OrientedBox {
+	return orientedBounds(convexHull(p.Points))
+}
+
+// ConvexHull returns the convex hull of the points of all subpaths as a new Path, see Path.ConvexHull
+func (c *Compound) ConvexHull() *Path {
+	return &Path{Points: closeHull(convexHull(c.points())), Anchor: c.Anchor}
+}
+
+// MinEnclosingCircle returns the center and radius of the smallest circle that contains all subpaths
+func (c *Compound) MinEnclosingCircle() (v.Vec, float64) {
+	return enclosingCircle(convexHull(c.points()))
+}
+
+// OrientedBounds returns the smallest area rectangle that contains all subpaths, see Path.OrientedBounds
+func (c *Compound) OrientedBounds() OrientedBox {
+	return orientedBounds(convexHull(c.points()))
+}
+
+// points returns the points of all subpaths
+func (c *Compound) points() []v.Vec {
+	var pts []v.Vec
+	for _, p := range c.Paths {
+		pts = append(pts, p.Points...)
+	}
+	return pts
+}
+
+// convexHull returns the hull vertices without the closing point using the monotone chain algorithm
+func convexHull(points []v.Vec) []v.Vec {
+	pts := slices.Clone(points)
+	slices.SortFunc(pts, func(a, b v.Vec) int {
+		if c := cmp.Compare(a.X, b.X); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Y, b.Y)
+	})
+	pts = slices.Compact(pts)
+	if len(pts) < 3 {
+		return pts
+	}
+	hull := make([]v.Vec, 0, 2*len(pts))
+	// Lower chain left to right, then upper chain right to left
+	for pass := range 2 {
+		start := len(hull)
+		for _, pt := range pts {
+			for len(hull) >= start+2 && hull[len(hull)-1].Sub(hull[len(hull)-2]).Cross(pt.Sub(hull[len(hull)-2])) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, pt)
+		}
+		hull = hull[:len(hull)-1]
+		if pass == 0 {
+			slices.Reverse(pts)
+		}
+	}
+	return hull
+}
+
+// closeHull appends the closing point to hulls with area
+func closeHull(hull []v.Vec) []v.Vec {
+	if len(hull) < 3 {
+		return hull
+	}
+	return append(hull, hull[0])
+}
+
+// enclosingCircle returns the smallest circle around pts with Welzl's algorithm
+func enclosingCircle(pts []v.Vec) (v.Vec, float64) {
+	if len(pts) == 0 {
+		return v.Vec{}, 0
+	}
+	// Shuffled for the expected linear time, the seed keeps results repeatable
+	pts = slices.Clone(pts)
+	rng := rand.New(rand.NewPCG(1, uint64(len(pts))))
+	rng.Shuffle(len(pts), func(i, j int) { pts[i], pts[j] = pts[j], pts[i] })
+	center, radius := pts[0], 0.0
+	inside := func(pt v.Vec) bool {
+		return pt.Dist(center) <= radius*(1+1e-12)+1e-12
+	}
+	for i := 1; i < len(pts); i++ {
+		if inside(pts[i]) {
+			continue
+		}
+		center, radius = pts[i], 0
+		for j := range i {
+			if inside(pts[j]) {
+				continue
+			}
+			center = pts[i].Lerp(pts[j], 0.5)
+			radius = center.Dist(pts[i])
+			for k := range j {
+				if !inside(pts[k]) {
+					center, radius = circumcircle(pts[i], pts[j], pts[k])
+				}
+			}
+		}
+	}
+	return center, radius
+}
+
+// circumcircle returns the circle through a, b and c.
+// Collinear points give the circle around the two farthest apart.
+func circumcircle(a, b, c v.Vec) (v.Vec, float64) {
+	ab, ac := b.Sub(a), c.Sub(a)
+	d := 2 * ab.Cross(ac)
+	if math.Abs(d) < 1e-12*ab.MagSq()*ac.MagSq() || d == 0 {
+		pairs := [3][2]v.Vec{{a, b}, {a, c}, {b, c}}
+		far := slices.MaxFunc(pairs[:], func(p, q [2]v.Vec) int {
+			return cmp.Compare(p[0].DistSq(p[1]), q[0].DistSq(q[1]))
+		})
+		center := far[0].Lerp(far[1], 0.5)
+		return center, center.Dist(far[0])
+	}
+	lb, lc := ab.MagSq(), ac.MagSq()
+	center := a.Add(v.Vec{X: ac.Y*lb - ab.Y*lc, Y: ab.X*lc - ac.X*lb}.DivS(d))
+	return center, center.Dist(a)
+}
+
+// orientedBounds returns the minimum area box of a convex hull with rotating calipers
+func orientedBounds(hull []v.Vec) OrientedBox {
+	switch len(hull) {
+	case 0:
+		return OrientedBox{}
+	case 1:
+		return OrientedBox{Center: hull[0]}
+	case 2:
+		d := hull[1].Sub(hull[0])
+		return OrientedBox{
+			Center: hull[0].Lerp(hull[1], 0.5),
+			Size:   v.Vec{X: d.Mag()},
+			Angle:  math.Atan2(d.Y, d.X),
+		}
+	}
+	n := len(hull)
+	next := func(i int) int { return (i + 1) % n }
+	best := OrientedBox{Size: v.Vec{X: math.Inf(1), Y: math.Inf(1)}}
+	// Indices of the points farthest along the edge, before it and away from it
+	right, left, top := 0, 0, 0
+	for i := range n {
+		a := hull[i]
+		u := hull[next(i)].Sub(a).Unit()
+		nrm := v.Vec{X: -u.Y, Y: u.X}
+		along := func(k int) float64 { return hull[k].Sub(a).Dot(u) }
+		across := func(k int) float64 { return hull[k].Sub(a).Dot(nrm) }
+		for range n {
+			if along(next(right)) <= along(right) {
+				break
+			}
+			right = next(right)
+		}
+		if i == 0 {
+			top = right
+		}
+		for range n {
+			if across(next(top)) <= across(top) {
+				break
+			}
+			top = next(top)
+		}
+		if i == 0 {
+			left = top
+		}
+		for range n {
+			if along(next(left)) >= along(left) {
+				break
+			}
+			left = next(left)
+		}
+		minU, maxU, height := along(left), along(right), across(top)
+		if width := maxU - minU; width*height < best.Area() {
+			best = OrientedBox{
+				Center: a.Add(u.Scale((minU + maxU) / 2)).Add(nrm.Scale(height / 2)),
+				Size:   v.Vec{X: width, Y: height},
+				Angle:  math.Atan2(u.Y, u.X),
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,162 @@
+package path
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// Crossing is a point where two paths, or two parts of one path, meet
+type Crossing struct {
+	Point v.Vec
+	// Segment indices, segment i runs from Points[i] to Points[i+1]
+	IndexA, IndexB int
+	// Arc lengths of Point from the start of each path
+	LengthA, LengthB float64
+}
+
+// Intersections returns all points where a and b cross or touch, sorted by LengthA.
+//
+// A crossing at a shared point of two segments is reported once. Collinear overlapping
+// segments report the ends of the overlap. Segments are found with a sweep over their bounding
+// boxes, so paths with many points stay fast as long as few segments overlap.
+func Intersections(a, b *Path) []Crossing {
+	segs := append(sweepSegments(a, 0), sweepSegments(b, 1)...)
+	var crossings []Crossing
+	sweep(segs, func(s, t *sweepSegment) {
+		if s.path == t.path {
+			return
+		}
+		if s.path == 1 {
+			s, t = t, s
+		}
+		crossings = intersectSegments(crossings, s, t)
+	})
+	sortCrossings(crossings)
+	return crossings
+}
+
+// SelfIntersections returns all points where the Path crosses or touches itself, sorted by LengthA.
+//
+// Each crossing is reported once with IndexA < IndexB. The point shared by neighbouring segments
+// is not reported, but a path that folds back over itself along a line reports the end of the overlap.
+// See Intersections.
+func (p *Path) SelfIntersections() []Crossing {
+	segs := sweepSegments(p, 0)
+	var crossings []Crossing
+	sweep(segs, func(s, t *sweepSegment) {
+		if s.index > t.index {
+			s, t = t, s
+		}
+		// Segments do not own their end point, so the point neighbours share is left out
+		crossings = intersectSegments(crossings, s, t)
+	})
+	sortCrossings(crossings)
+	return crossings
+}
+
+// sweepSegment is a segment of a path with its bounds
+type sweepSegment struct {
+	a, b        v.Vec
+	path, index int
+	// length is the arc length at a
+	length float64
+	// last is true for the last segment of an open path, its end point belongs to it
+	last                   bool
+	minX, maxX, minY, maxY float64
+}
+
+func sweepSegments(p *Path, id int) []sweepSegment {
+	if len(p.Points) < 2 {
+		return nil
+	}
+	segs := make([]sweepSegment, len(p.Points)-1)
+	length := 0.0
+	for i := range segs {
+		a, b := p.Points[i], p.Points[i+1]
+		segs[i] = sweepSegment{
+			a: a, b: b, path: id, index: i, length: length,
+			minX: min(a.X, b.X), maxX: max(a.X, b.X),
+			minY: min(a.Y, b.Y), maxY: max(a.Y, b.Y),
+		}
+		length += a.Dist(b)
+	}
+	segs[len(segs)-1].last = !p.IsClosed()
+	return segs
+}
+
+// sweep calls fn with every pair of segments whose bounding boxes overlap or nearly touch
+func sweep(segs []sweepSegment, fn func(s, t *sweepSegment)) {
+	const slack = 1e-9
+	slices.SortFunc(segs, func(s, t sweepSegment) int {
+		return cmp.Compare(s.minX, t.minX)
+	})
+	for i := range segs {
+		s := &segs[i]
+		for j := i + 1; j < len(segs) && segs[j].minX <= s.maxX+slack; j++ {
+			t := &segs[j]
+			if t.maxY >= s.minY-slack && t.minY <= s.maxY+slack {
+				fn(s, t)
+			}
+		}
+	}
+}
+
+// intersectSegments appends the crossings of s and t.
+// Segments own their start point but not their end point, except the last segment of an open path.
+func intersectSegments(crossings []Crossing, s, t *sweepSegment) []Crossing {
+	d1, d2 := s.b.Sub(s.a), t.b.Sub(t.a)
+	w := t.a.Sub(s.a)
+	den := d1.Cross(d2)
+	// Parameters within eps of the segment ends are snapped to them
+	const eps = 1e-9
+	add := func(ts, tt float64) {
+		if ts < -eps || tt < -eps || ts > 1+eps || tt > 1+eps ||
+			ts > 1-eps && !s.last || tt > 1-eps && !t.last {
+			return
+		}
+		ts, tt = min(max(ts, 0), 1), min(max(tt, 0), 1)
+		crossings = append(crossings, Crossing{
+			Point:   s.a.Lerp(s.b, ts),
+			IndexA:  s.index,
+			IndexB:  t.index,
+			LengthA: s.length + ts*d1.Mag(),
+			LengthB: t.length + tt*d2.Mag(),
+		})
+	}
+	l1, l2 := d1.MagSq(), d2.MagSq()
+	if math.Abs(den) > 1e-12*math.Sqrt(l1*l2) {
+		add(w.Cross(d2)/den, w.Cross(d1)/den)
+		return crossings
+	}
+	// Parallel segments only meet when they are collinear
+	if l1 == 0 || l2 == 0 || math.Abs(w.Cross(d1)) > 1e-9*math.Sqrt(l1)*max(1, math.Sqrt(l1)) {
+		return crossings
+	}
+	// Ends of the overlap in the parameters of s
+	t0 := w.Dot(d1) / l1
+	t1 := t.b.Sub(s.a).Dot(d1) / l1
+	lo, hi := max(min(t0, t1), 0), min(max(t0, t1), 1)
+	if lo > hi {
+		return crossings
+	}
+	for _, ts := range []float64{lo, hi} {
+		pt := s.a.Lerp(s.b, ts)
+		add(ts, pt.Sub(t.a).Dot(d2)/l2)
+		if lo == hi {
+			break
+		}
+	}
+	return crossings
+}
+
+func sortCrossings(crossings []Crossing) {
+	slices.SortFunc(crossings, func(x, y Crossing) int {
+		if c := cmp.Compare(x.LengthA, y.LengthA); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.LengthB, y.LengthB)
+	})
+}
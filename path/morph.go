@@ -0,0 +1,119 @@
+package path
+
+import (
+	"math"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// Morph interpolates between two paths with different point counts
+type Morph struct {
+	// Ease maps t before interpolating, nil is linear. anim easing functions can be used.
+	Ease func(float64) float64
+
+	from, to             []v.Vec
+	fromAnchor, toAnchor v.Vec
+	closed               bool
+}
+
+// NewMorph returns a Morph from a to b. The paths are not changed.
+//
+// Both paths are resampled to samples points evenly spaced by arc length, zero or less uses the larger
+// point count. Closed paths are aligned to the start point and winding direction of b that make
+// the shortest travel when t goes from 0 to 1. If either path is open, both are morphed as open
+// paths, a closed path keeps its closing point and b may be reversed.
+func NewMorph(a, b *Path, samples int) *Morph {
+	if samples <= 0 {
+		samples = max(a.Len(), b.Len())
+	}
+	samples = max(samples, 3)
+	m := &Morph{fromAnchor: a.Anchor, toAnchor: b.Anchor}
+	m.closed = a.Len() > 3 && b.Len() > 3 && a.IsClosed() && b.IsClosed()
+	from, to := resampled(a, samples, m.closed), resampled(b, samples, m.closed)
+	reversed := slices.Clone(to)
+	slices.Reverse(reversed)
+	m.from = from
+	if m.closed {
+		forward, cost := alignRing(from, to)
+		backward, reversedCost := alignRing(from, reversed)
+		m.to = forward
+		if reversedCost < cost {
+			m.to = backward
+		}
+		return m
+	}
+	rel := centered(from)
+	m.to = to
+	if morphCost(rel, centered(reversed), 0) < morphCost(rel, centered(to), 0) {
+		m.to = reversed
+	}
+	return m
+}
+
+// resampled returns n points evenly spaced along p. Rings leave out the closing point,
+// open morphs keep it. Paths without length repeat their start point.
+func resampled(p *Path, n int, ring bool) []v.Vec {
+	count := n
+	if !ring && p.IsClosed() {
+		// n-1 points around the loop plus the closing point
+		count = n - 1
+	}
+	pts := p.Clone().Resample(count).Points
+	if len(pts) < n {
+		pts = slices.Repeat([]v.Vec{p.Start()}, n)
+	}
+	return pts[:n]
+}
+
+// At returns the interpolated Path at t, 0 is the first path and 1 is the second
+func (m *Morph) At(t float64) *Path {
+	if m.Ease != nil {
+		t = m.Ease(t)
+	}
+	pts := make([]v.Vec, len(m.from), len(m.from)+1)
+	for i, pt := range m.from {
+		pts[i] = pt.Lerp(m.to[i], t)
+	}
+	if m.closed {
+		pts = append(pts, pts[0])
+	}
+	return &Path{Points: pts, Anchor: m.fromAnchor.Lerp(m.toAnchor, t)}
+}
+
+// alignRing returns the points of to rotated so that they travel the least to the points of from,
+// and the travel as the sum of squared distances. Positions are compared relative to the
+// centroids, so distant shapes still align by form.
+func alignRing(from, to []v.Vec) ([]v.Vec, float64) {
+	rel, relTo := centered(from), centered(to)
+	best, bestCost := 0, math.Inf(1)
+	for k := range len(to) {
+		if cost := morphCost(rel, relTo, k); cost < bestCost {
+			best, bestCost = k, cost
+		}
+	}
+	return append(slices.Clone(to[best:]), to[:best]...), bestCost
+}
+
+// morphCost returns the sum of squared distances between a[i] and b[(i+offset)%n]
+func morphCost(a, b []v.Vec, offset int) float64 {
+	cost := 0.0
+	for i, pt := range a {
+		cost += pt.DistSq(b[(i+offset)%len(b)])
+	}
+	return cost
+}
+
+// centered returns the points relative to their centroid
+func centered(pts []v.Vec) []v.Vec {
+	c := v.Vec{}
+	for _, pt := range pts {
+		c = c.Add(pt)
+	}
+	c = c.DivS(float64(len(pts)))
+	rel := make([]v.Vec, len(pts))
+	for i, pt := range pts {
+		rel[i] = pt.Sub(c)
+	}
+	return rel
+}
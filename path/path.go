@@ -5,6 +5,7 @@ import (
 	"math"
 	"slices"
 
+	"github.com/setanarut/gog/v2/affine"
 	"github.com/setanarut/gog/v2/utils"
 	"github.com/setanarut/v"
 )
@@ -15,6 +16,9 @@ type Path struct {
 	Anchor v.Vec
 	// Points holds coordinates of Path
 	Points []v.Vec
+
+	// rev is incremented when the points are changed, see ArcLength
+	rev uint64
 }
 
 // NewPath returns new Path from points
@@ -34,6 +38,7 @@ func NewPath(points []v.Vec) *Path {
 // If the length is necessary, length should be taken with Length().
 func (p *Path) AppendPoints(points ...v.Vec) *Path {
 	p.Points = append(p.Points, points...)
+	p.changed()
 	return p
 }
 
@@ -44,6 +49,7 @@ func (p *Path) AppendPoints(points ...v.Vec) *Path {
 func (p *Path) DeleteEnd() *Path {
 	if len(p.Points) > 2 {
 		p.Points = p.Points[:len(p.Points)-1]
+		p.changed()
 	}
 	return p
 }
@@ -55,6 +61,7 @@ func (p *Path) DeleteEnd() *Path {
 func (p *Path) DeleteAtIndex(index int) *Path {
 	if len(p.Points) > 2 {
 		p.Points = slices.Delete(p.Points, index, index+1)
+		p.changed()
 	}
 	return p
 }
@@ -65,6 +72,7 @@ func (p *Path) DeleteAtIndex(index int) *Path {
 // If the length is necessary, length should be taken with Length().
 func (p *Path) InsertAtIndex(pnt v.Vec, index int) *Path {
 	p.Points = slices.Insert(p.Points, index, pnt)
+	p.changed()
 	return p
 }
 
@@ -108,6 +116,7 @@ func (p *Path) RemoveDoubles() *Path {
 		}
 	}
 	p.Points = uniquePoints
+	p.changed()
 	if closed {
 		p.Close()
 	}
@@ -129,6 +138,7 @@ func (p *Path) ResetAnchor() *Path {
 func (p *Path) Open() *Path {
 	if p.IsClosed() {
 		p.Points = p.Points[:len(p.Points)-1]
+		p.changed()
 		return p
 	}
 	return p
@@ -141,6 +151,7 @@ func (p *Path) Open() *Path {
 func (p *Path) Close() *Path {
 	if !p.IsClosed() {
 		p.Points = append(p.Points, p.Points[0])
+		p.changed()
 	}
 	return p
 }
@@ -156,6 +167,8 @@ func (p *Path) Perpendicular(length float64, lineLength float64) (start v.Vec, e
 
 // Centroid calculates and returns the path's centroid point.
 // Costly operation. Don't use unless necessary.
+//
+// It is the average of the points, use AreaCentroid for the center of the filled area.
 func (p *Path) Centroid() v.Vec {
 	total := float64(len(p.Points))
 	centroidPoint := v.Vec{0, 0}
@@ -215,6 +228,7 @@ func (p *Path) PrintPoints() {
 // SetPoints sets points
 func (p *Path) SetPoints(pts []v.Vec) {
 	p.Points = pts
+	p.changed()
 }
 
 // Bounds returns bounds min/max
@@ -268,6 +282,7 @@ func (p *Path) Translate(x, y float64) *Path {
 		p.Points[i] = p.Points[i].Add(q)
 	}
 	p.Anchor = p.Anchor.Add(q)
+	p.changed()
 	return p
 }
 
@@ -276,6 +291,7 @@ func (p *Path) Rotate(angle float64) *Path {
 	for i := 0; i < p.Len(); i++ {
 		p.Points[i] = utils.RotateAbout(p.Points[i], angle, p.Anchor)
 	}
+	p.changed()
 	return p
 }
 
@@ -290,6 +306,17 @@ func (p *Path) Scale(factor v.Vec) *Path {
 	for i := 0; i < len(p.Points); i++ {
 		p.Points[i] = factor.Mul(p.Points[i].Sub(p.Anchor)).Add(p.Anchor)
 	}
+	p.changed()
+	return p
+}
+
+// Transform applies the affine matrix to the points and the anchor of the Path
+func (p *Path) Transform(m affine.Matrix) *Path {
+	for i, pt := range p.Points {
+		p.Points[i] = m.Apply(pt)
+	}
+	p.Anchor = m.Apply(p.Anchor)
+	p.changed()
 	return p
 }
 
@@ -307,6 +334,7 @@ func (p *Path) Length() float64 {
 // The starting point becomes the end and the end becomes the beginning.
 func (p *Path) Reverse() *Path {
 	slices.Reverse(p.Points)
+	p.changed()
 	return p
 }
 
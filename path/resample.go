@@ -0,0 +1,68 @@
+package path
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Resample replaces the points with n points evenly spaced by arc length.
+//
+// The start and end points of an open Path are kept. A closed Path gets n points
+// around the loop plus the closing point, starting at the current start point.
+// The anchor is not changed. Paths shorter than two points or with zero length are not changed.
+func (p *Path) Resample(n int) *Path {
+	length := p.Length()
+	if len(p.Points) < 2 || length == 0 || n < 2 {
+		return p
+	}
+	if p.IsClosed() {
+		p.Points = p.sampleEvenly(n, length/float64(n))
+		p.Points = append(p.Points, p.Points[0])
+		p.changed()
+		return p
+	}
+	end := p.End()
+	p.Points = p.sampleEvenly(n-1, length/float64(n-1))
+	p.Points = append(p.Points, end)
+	p.changed()
+	return p
+}
+
+// ResampleBySpacing replaces the points with points evenly spaced by arc length,
+// the spacing is adjusted to the nearest value that divides the length evenly.
+//
+// See Resample.
+func (p *Path) ResampleBySpacing(spacing float64) *Path {
+	length := p.Length()
+	if spacing <= 0 || length == 0 {
+		return p
+	}
+	segments := max(1, int(math.Round(length/spacing)))
+	if p.IsClosed() {
+		return p.Resample(max(3, segments))
+	}
+	return p.Resample(segments + 1)
+}
+
+// sampleEvenly returns n points from the start at multiples of step along the Path
+func (p *Path) sampleEvenly(n int, step float64) []v.Vec {
+	pts := make([]v.Vec, 0, n+1)
+	i := 0
+	segStart := 0.0
+	segLength := p.Points[0].Dist(p.Points[1])
+	for k := range n {
+		target := float64(k) * step
+		for segStart+segLength < target && i < len(p.Points)-2 {
+			segStart += segLength
+			i++
+			segLength = p.Points[i].Dist(p.Points[i+1])
+		}
+		t := 0.0
+		if segLength > 0 {
+			t = min(1, (target-segStart)/segLength)
+		}
+		pts = append(pts, p.Points[i].Lerp(p.Points[i+1], t))
+	}
+	return pts
+}
@@ -0,0 +1,250 @@
+package path
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// SegmentKind constants determine the geometry of a Segment
+const (
+	LineSegment SegmentKind = iota
+	QuadSegment
+	CubicSegment
+	ArcSegment
+)
+
+type SegmentKind uint8
+
+// maxSubdivision limits recursive subdivision of curves
+const maxSubdivision = 16
+
+// Segment is one piece of a Curve, it starts at the end of the previous segment.
+type Segment struct {
+	Kind SegmentKind
+	// Bezier control points. QuadSegment uses Ctrl1 only.
+	Ctrl1, Ctrl2 v.Vec
+	// End point of the segment
+	End v.Vec
+	// Arc parameters. Points of the elliptical arc are
+	//
+	//	Center + AxisX*cos(θ) + AxisY*sin(θ)
+	//
+	// for θ from StartAngle to StartAngle+Sweep (radians).
+	// Axis vectors keep the arc exact under any affine transform.
+	Center, AxisX, AxisY v.Vec
+	StartAngle, Sweep    float64
+}
+
+// pointAt returns point at parameter t in [0,1], start is the end point of the previous segment
+func (s *Segment) pointAt(start v.Vec, t float64) v.Vec {
+	switch s.Kind {
+	case QuadSegment:
+		mt := 1 - t
+		return start.Scale(mt * mt).Add(s.Ctrl1.Scale(2 * mt * t)).Add(s.End.Scale(t * t))
+	case CubicSegment:
+		mt := 1 - t
+		return start.Scale(mt * mt * mt).
+			Add(s.Ctrl1.Scale(3 * mt * mt * t)).
+			Add(s.Ctrl2.Scale(3 * mt * t * t)).
+			Add(s.End.Scale(t * t * t))
+	case ArcSegment:
+		sin, cos := math.Sincos(s.StartAngle + s.Sweep*t)
+		return s.Center.Add(s.AxisX.Scale(cos)).Add(s.AxisY.Scale(sin))
+	}
+	return start.Lerp(s.End, t)
+}
+
+// derivativeAt returns the derivative with respect to t
+func (s *Segment) derivativeAt(start v.Vec, t float64) v.Vec {
+	switch s.Kind {
+	case QuadSegment:
+		return s.Ctrl1.Sub(start).Scale(2 * (1 - t)).Add(s.End.Sub(s.Ctrl1).Scale(2 * t))
+	case CubicSegment:
+		mt := 1 - t
+		return s.Ctrl1.Sub(start).Scale(3 * mt * mt).
+			Add(s.Ctrl2.Sub(s.Ctrl1).Scale(6 * mt * t)).
+			Add(s.End.Sub(s.Ctrl2).Scale(3 * t * t))
+	case ArcSegment:
+		sin, cos := math.Sincos(s.StartAngle + s.Sweep*t)
+		return s.AxisY.Scale(cos).Sub(s.AxisX.Scale(sin)).Scale(s.Sweep)
+	}
+	return s.End.Sub(start)
+}
+
+// tangentAt returns the direction of travel at t, it is non-zero even at degenerate control points
+func (s *Segment) tangentAt(start v.Vec, t float64) v.Vec {
+	d := s.derivativeAt(start, t)
+	if d.MagSq() > 1e-18 {
+		return d
+	}
+	// Nudge inwards when a control point coincides with an end point
+	const nudge = 1e-4
+	if t < 0.5 {
+		return s.pointAt(start, t+nudge).Sub(s.pointAt(start, t))
+	}
+	return s.pointAt(start, t).Sub(s.pointAt(start, t-nudge))
+}
+
+// flatten appends the polyline of the segment (without start) to pts
+func (s *Segment) flatten(pts []v.Vec, start v.Vec, tolerance float64) []v.Vec {
+	switch s.Kind {
+	case QuadSegment:
+		c1, c2 := quadToCubic(start, s.Ctrl1, s.End)
+		return subdivideCubic(pts, start, c1, c2, s.End, tolerance*tolerance, 0)
+	case CubicSegment:
+		return subdivideCubic(pts, start, s.Ctrl1, s.Ctrl2, s.End, tolerance*tolerance, 0)
+	case ArcSegment:
+		r := max(s.AxisX.Mag(), s.AxisY.Mag())
+		step := math.Pi / 4
+		if tolerance < r {
+			step = min(step, 2*math.Acos(1-tolerance/r))
+		}
+		n := int(math.Ceil(math.Abs(s.Sweep) / step))
+		for i := 1; i < n; i++ {
+			pts = append(pts, s.pointAt(start, float64(i)/float64(n)))
+		}
+	}
+	return append(pts, s.End)
+}
+
+// trace sends the segment to pen, arcs are approximated with cubic beziers of at most 90°
+func (s *Segment) trace(pen Pen) {
+	switch s.Kind {
+	case QuadSegment:
+		pen.QuadBezier(s.Ctrl1, s.End)
+	case CubicSegment:
+		pen.CubeBezier(s.Ctrl1, s.Ctrl2, s.End)
+	case ArcSegment:
+		n := max(1, int(math.Ceil(math.Abs(s.Sweep)/(math.Pi/2)-1e-9)))
+		d := s.Sweep / float64(n)
+		h := 4.0 / 3.0 * math.Tan(d/4)
+		for i := range n {
+			a := s.StartAngle + d*float64(i)
+			b := a + d
+			sa, ca := math.Sincos(a)
+			sb, cb := math.Sincos(b)
+			// Unit circle bezier mapped by the arc axes
+			mapPoint := func(x, y float64) v.Vec {
+				return s.Center.Add(s.AxisX.Scale(x)).Add(s.AxisY.Scale(y))
+			}
+			end := mapPoint(cb, sb)
+			if i == n-1 {
+				end = s.End
+			}
+			pen.CubeBezier(mapPoint(ca-h*sa, sa+h*ca), mapPoint(cb+h*sb, sb-h*cb), end)
+		}
+	default:
+		pen.Line(s.End)
+	}
+}
+
+// length returns arc length between parameters t0 and t1
+func (s *Segment) length(start v.Vec, t0, t1 float64) float64 {
+	if s.Kind == LineSegment {
+		return start.Dist(s.End) * (t1 - t0)
+	}
+	speed := func(t float64) float64 {
+		return s.derivativeAt(start, t).Mag()
+	}
+	whole := gaussLegendre(speed, t0, t1)
+	return adaptiveLength(speed, t0, t1, whole, 0)
+}
+
+// paramAtLength returns parameter t where the arc length from 0 to t equals length
+func (s *Segment) paramAtLength(start v.Vec, length, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	t := length / total
+	if s.Kind == LineSegment {
+		return t
+	}
+	lo, hi := 0.0, 1.0
+	for range 32 {
+		f := s.length(start, 0, t) - length
+		if math.Abs(f) < 1e-9*max(1, total) {
+			break
+		}
+		if f > 0 {
+			hi = t
+		} else {
+			lo = t
+		}
+		// Newton step, fall back to bisection if it leaves the bracket
+		next := t
+		if d := s.derivativeAt(start, t).Mag(); d > 0 {
+			next = t - f/d
+		}
+		if next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		t = next
+	}
+	return t
+}
+
+// transform maps all points of the segment with point and the arc axes with vec
+func (s *Segment) transform(point, vec func(v.Vec) v.Vec) {
+	s.Ctrl1 = point(s.Ctrl1)
+	s.Ctrl2 = point(s.Ctrl2)
+	s.End = point(s.End)
+	s.Center = point(s.Center)
+	s.AxisX = vec(s.AxisX)
+	s.AxisY = vec(s.AxisY)
+}
+
+func quadToCubic(p0, c, p1 v.Vec) (v.Vec, v.Vec) {
+	return p0.Add(c.Sub(p0).Scale(2.0 / 3.0)), p1.Add(c.Sub(p1).Scale(2.0 / 3.0))
+}
+
+func subdivideCubic(pts []v.Vec, p0, c1, c2, p1 v.Vec, tolSq float64, depth int) []v.Vec {
+	if depth >= maxSubdivision || cubicFlatnessSq(p0, c1, c2, p1) <= tolSq {
+		return append(pts, p1)
+	}
+	// de Casteljau split at t=0.5
+	p01 := p0.Lerp(c1, 0.5)
+	p12 := c1.Lerp(c2, 0.5)
+	p23 := c2.Lerp(p1, 0.5)
+	p012 := p01.Lerp(p12, 0.5)
+	p123 := p12.Lerp(p23, 0.5)
+	mid := p012.Lerp(p123, 0.5)
+	pts = subdivideCubic(pts, p0, p01, p012, mid, tolSq, depth+1)
+	return subdivideCubic(pts, mid, p123, p23, p1, tolSq, depth+1)
+}
+
+// cubicFlatnessSq returns an upper bound of the squared distance between the curve and its chord.
+func cubicFlatnessSq(p0, c1, c2, p1 v.Vec) float64 {
+	ux := 3*c1.X - 2*p0.X - p1.X
+	uy := 3*c1.Y - 2*p0.Y - p1.Y
+	vx := 3*c2.X - p0.X - 2*p1.X
+	vy := 3*c2.Y - p0.Y - 2*p1.Y
+	return (max(ux*ux, vx*vx) + max(uy*uy, vy*vy)) / 16
+}
+
+// 5-point Gauss-Legendre nodes and weights on [-1, 1]
+var (
+	glNodes   = [5]float64{0, -0.5384693101056831, 0.5384693101056831, -0.9061798459386640, 0.9061798459386640}
+	glWeights = [5]float64{0.5688888888888889, 0.4786286704993665, 0.4786286704993665, 0.2369268850561891, 0.2369268850561891}
+)
+
+func gaussLegendre(f func(float64) float64, a, b float64) float64 {
+	h := (b - a) / 2
+	m := (a + b) / 2
+	sum := 0.0
+	for i, x := range glNodes {
+		sum += glWeights[i] * f(m+h*x)
+	}
+	return sum * h
+}
+
+// adaptiveLength integrates f, halving the interval until both halves agree with the whole
+func adaptiveLength(f func(float64) float64, a, b, whole float64, depth int) float64 {
+	m := (a + b) / 2
+	left := gaussLegendre(f, a, m)
+	right := gaussLegendre(f, m, b)
+	if depth >= maxSubdivision || math.Abs(left+right-whole) <= 1e-9*max(1, whole) {
+		return left + right
+	}
+	return adaptiveLength(f, a, m, left, depth+1) + adaptiveLength(f, m, b, right, depth+1)
+}
@@ -0,0 +1,49 @@
+package path
+
+import "github.com/setanarut/v"
+
+// FillRule constants determine which regions of a self-intersecting or compound shape are inside
+const (
+	// NonZero fills regions with a non-zero winding number
+	NonZero FillRule = iota
+	// EvenOdd fills regions crossed an odd number of times, overlapping subpaths become holes
+	EvenOdd
+)
+
+type FillRule uint8
+
+// Pen receives the outline of a Shape as path commands.
+//
+// Every subpath begins with Start and ends with Stop.
+type Pen interface {
+	// Start starts a new subpath at pt
+	Start(pt v.Vec)
+	// Line adds a line segment to pt
+	Line(pt v.Vec)
+	// QuadBezier adds a quadratic bezier segment to pt
+	QuadBezier(ctrl, pt v.Vec)
+	// CubeBezier adds a cubic bezier segment to pt
+	CubeBezier(ctrl1, ctrl2, pt v.Vec)
+	// Stop ends the subpath, closed reports whether it returns to its start point
+	Stop(closed bool)
+}
+
+// Shape is geometry that can be filled and stroked by gog.Context.
+//
+// It is implemented by *Path, *Curve and *Compound.
+type Shape interface {
+	// Trace sends the outline of the shape to pen
+	Trace(pen Pen)
+}
+
+// Trace sends the outline of the Path to pen
+func (p *Path) Trace(pen Pen) {
+	if len(p.Points) == 0 {
+		return
+	}
+	pen.Start(p.Points[0])
+	for i := 1; i < len(p.Points); i++ {
+		pen.Line(p.Points[i])
+	}
+	pen.Stop(p.IsClosed())
+}
@@ -0,0 +1,255 @@
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Simplify removes points with the Ramer–Douglas–Peucker algorithm, the Path stays within
+// tolerance distance of the original.
+//
+// Open paths keep their end points. Closed paths stay closed with at least 3 points.
+func (p *Path) Simplify(tolerance float64) *Path {
+	ring, closed := p.ring()
+	if len(ring) < 3 {
+		return p
+	}
+	keep := make([]bool, len(ring))
+	if closed {
+		// Split the loop at the point farthest from the start
+		far := 0
+		for i, pt := range ring {
+			if pt.DistSq(ring[0]) > ring[far].DistSq(ring[0]) {
+				far = i
+			}
+		}
+		loop := append(ring[:len(ring):len(ring)], ring[0])
+		keep = append(keep, true)
+		keep[0], keep[far] = true, true
+		rdp(loop, keep, 0, far, tolerance)
+		rdp(loop, keep, far, len(ring), tolerance)
+		keep = keep[:len(ring)]
+	} else {
+		keep[0], keep[len(ring)-1] = true, true
+		rdp(ring, keep, 0, len(ring)-1, tolerance)
+	}
+	pts := make([]v.Vec, 0, len(ring))
+	for i, pt := range ring {
+		if keep[i] {
+			pts = append(pts, pt)
+		}
+	}
+	if closed && len(pts) < 3 {
+		return p
+	}
+	return p.setRing(pts, closed)
+}
+
+// rdp marks the points between first and last that must be kept
+func rdp(pts []v.Vec, keep []bool, first, last int, tolerance float64) {
+	for last-first > 1 {
+		index, dist := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			if d := distToSegment(pts[i], pts[first], pts[last]); d > dist {
+				index, dist = i, d
+			}
+		}
+		if index < 0 {
+			return
+		}
+		keep[index] = true
+		rdp(pts, keep, first, index, tolerance)
+		first = index
+	}
+}
+
+// SimplifyVW removes points with the Visvalingam–Whyatt algorithm.
+//
+// The point that forms the smallest triangle with its neighbours is removed until all
+// triangles are larger than minArea. It keeps the overall shape better than Simplify
+// on smooth curves. Open paths keep their end points. Closed paths stay closed with at least 3 points.
+func (p *Path) SimplifyVW(minArea float64) *Path {
+	ring, closed := p.ring()
+	n := len(ring)
+	if n < 3 {
+		return p
+	}
+	prev, next := make([]int, n), make([]int, n)
+	for i := range ring {
+		prev[i], next[i] = i-1, i+1
+	}
+	if closed {
+		prev[0], next[n-1] = n-1, 0
+	}
+	area := func(i int) float64 {
+		if prev[i] < 0 || next[i] >= n {
+			return math.Inf(1)
+		}
+		a, b, c := ring[prev[i]], ring[i], ring[next[i]]
+		return math.Abs(b.Sub(a).Cross(c.Sub(a))) / 2
+	}
+	q := make(vwQueue, n)
+	items := make([]*vwItem, n)
+	for i := range ring {
+		items[i] = &vwItem{index: i, area: area(i), pos: i}
+		q[i] = items[i]
+	}
+	heap.Init(&q)
+	remaining, least := n, 2
+	if closed {
+		least = 3
+	}
+	removed := make([]bool, n)
+	for q.Len() > 0 && remaining > least {
+		it := q[0]
+		if it.area > minArea {
+			break
+		}
+		heap.Pop(&q)
+		removed[it.index] = true
+		remaining--
+		pi, ni := prev[it.index], next[it.index]
+		if pi >= 0 {
+			next[pi] = ni
+		}
+		if ni < n {
+			prev[ni] = pi
+		}
+		// Neighbours never get a smaller area than the removed point
+		for _, j := range []int{pi, ni} {
+			if j >= 0 && j < n && !removed[j] {
+				items[j].area = max(area(j), it.area)
+				heap.Fix(&q, items[j].pos)
+			}
+		}
+	}
+	pts := make([]v.Vec, 0, remaining)
+	for i, pt := range ring {
+		if !removed[i] {
+			pts = append(pts, pt)
+		}
+	}
+	return p.setRing(pts, closed)
+}
+
+type vwItem struct {
+	index, pos int
+	area       float64
+}
+
+// vwQueue is a min heap of point areas
+type vwQueue []*vwItem
+
+func (q vwQueue) Len() int { return len(q) }
+func (q vwQueue) Less(i, j int) bool {
+	if q[i].area == q[j].area {
+		return q[i].index < q[j].index
+	}
+	return q[i].area < q[j].area
+}
+func (q vwQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].pos, q[j].pos = i, j
+}
+func (q *vwQueue) Push(x any) {
+	it := x.(*vwItem)
+	it.pos = len(*q)
+	*q = append(*q, it)
+}
+func (q *vwQueue) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
+
+// Chaikin smooths the Path by cutting every corner iterations times.
+//
+// Each segment is replaced by points at 1/4 and 3/4 of its length, so the point count
+// roughly doubles per iteration. Open paths keep their end points.
+func (p *Path) Chaikin(iterations int) *Path {
+	ring, closed := p.ring()
+	if len(ring) < 3 {
+		return p
+	}
+	for range iterations {
+		pts := make([]v.Vec, 0, 2*len(ring))
+		segments := len(ring) - 1
+		if closed {
+			segments = len(ring)
+		} else {
+			pts = append(pts, ring[0])
+		}
+		for i := range segments {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if closed || i > 0 {
+				pts = append(pts, a.Lerp(b, 0.25))
+			}
+			if closed || i < segments-1 {
+				pts = append(pts, a.Lerp(b, 0.75))
+			}
+		}
+		if !closed {
+			pts = append(pts, ring[len(ring)-1])
+		}
+		ring = pts
+	}
+	return p.setRing(ring, closed)
+}
+
+// SmoothLaplacian moves every point towards the midpoint of its neighbours by factor, iterations times.
+//
+// factor is in the range 0-1. The point count does not change, but the Path shrinks a little
+// with every iteration. Open paths keep their end points.
+func (p *Path) SmoothLaplacian(iterations int, factor float64) *Path {
+	ring, closed := p.ring()
+	n := len(ring)
+	if n < 3 {
+		return p
+	}
+	pts := make([]v.Vec, n)
+	for range iterations {
+		for i, pt := range ring {
+			if !closed && (i == 0 || i == n-1) {
+				pts[i] = pt
+				continue
+			}
+			mid := ring[(i+n-1)%n].Add(ring[(i+1)%n]).Scale(0.5)
+			pts[i] = pt.Lerp(mid, factor)
+		}
+		ring, pts = pts, ring
+	}
+	return p.setRing(ring, closed)
+}
+
+// ring returns a copy of the points without the closing point and whether the Path is closed
+func (p *Path) ring() ([]v.Vec, bool) {
+	closed := len(p.Points) > 3 && p.IsClosed()
+	pts := p.Points
+	if closed {
+		pts = pts[:len(pts)-1]
+	}
+	return append([]v.Vec(nil), pts...), closed
+}
+
+// setRing replaces the points, closed rings get their closing point back
+func (p *Path) setRing(ring []v.Vec, closed bool) *Path {
+	if closed {
+		ring = append(ring, ring[0])
+	}
+	p.SetPoints(ring)
+	return p
+}
+
+// distToSegment returns distance of pt to the segment a-b
+func distToSegment(pt, a, b v.Vec) float64 {
+	d := b.Sub(a)
+	l := d.MagSq()
+	if l == 0 {
+		return pt.Dist(a)
+	}
+	t := min(max(pt.Sub(a).Dot(d)/l, 0), 1)
+	return pt.Dist(a.Add(d.Scale(t)))
+}
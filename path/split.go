@@ -0,0 +1,103 @@
+package path
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// SplitAtLength returns the parts of the Path before and after length as new paths.
+//
+// Both parts contain the point at length and keep the anchor of the Path. The Path is not changed.
+func (p *Path) SplitAtLength(length float64) (*Path, *Path) {
+	total := p.Length()
+	length = min(max(length, 0), total)
+	before := &Path{Points: p.between(0, length), Anchor: p.Anchor}
+	after := &Path{Points: p.between(length, total), Anchor: p.Anchor}
+	return before, after
+}
+
+// Trim keeps the part of the Path between startLength and endLength.
+//
+// Lengths are clamped to the Path on open paths. On closed paths they wrap around, so a start
+// after the end keeps the part that passes through the start point.
+// A start equal to the end leaves a single point.
+func (p *Path) Trim(startLength, endLength float64) *Path {
+	p.SetPoints(p.subPoints(startLength, endLength, p.Length()))
+	return p
+}
+
+// SubPath returns the part of the Path between normalized positions t0 and t1 as a new Path,
+// 0 is the start and 1 is the end. The Path is not changed.
+//
+// Animate t1 from 0 to 1 to draw the Path on. See Trim for open and closed paths.
+func (p *Path) SubPath(t0, t1 float64) *Path {
+	total := p.Length()
+	return &Path{Points: p.subPoints(t0*total, t1*total, total), Anchor: p.Anchor}
+}
+
+// subPoints returns the points between two lengths, wrapping around on closed paths
+func (p *Path) subPoints(l0, l1, total float64) []v.Vec {
+	if len(p.Points) < 2 || total == 0 {
+		return append([]v.Vec(nil), p.Points...)
+	}
+	if !p.IsClosed() {
+		l0, l1 = min(max(l0, 0), total), min(max(l1, 0), total)
+		return p.between(l0, max(l0, l1))
+	}
+	span := l1 - l0
+	if span >= total || span <= -total {
+		// A whole loop starting at l0
+		span = total
+	} else if span < 0 {
+		span += total
+	}
+	l0 = math.Mod(l0, total)
+	if l0 < 0 {
+		l0 += total
+	}
+	if l0+span <= total {
+		return p.between(l0, l0+span)
+	}
+	pts := p.between(l0, total)
+	return append(pts, p.between(0, l0+span-total)[1:]...)
+}
+
+// between returns the point at l0, the points strictly between l0 and l1 and the point at l1,
+// 0 <= l0 <= l1 <= length of the Path
+func (p *Path) between(l0, l1 float64) []v.Vec {
+	var pts []v.Vec
+	traveled := 0.0
+	for i := range len(p.Points) - 1 {
+		a, b := p.Points[i], p.Points[i+1]
+		segLength := a.Dist(b)
+		end := traveled + segLength
+		if pts == nil && l0 <= end {
+			pts = append(pts, pointOnSegment(a, b, l0-traveled, segLength))
+		}
+		if pts != nil {
+			if l1 <= end {
+				if pt := pointOnSegment(a, b, l1-traveled, segLength); pt != pts[len(pts)-1] {
+					pts = append(pts, pt)
+				}
+				return pts
+			}
+			if b != pts[len(pts)-1] {
+				pts = append(pts, b)
+			}
+		}
+		traveled = end
+	}
+	if pts == nil {
+		return []v.Vec{p.End()}
+	}
+	return pts
+}
+
+// pointOnSegment returns the point at distance from a towards b
+func pointOnSegment(a, b v.Vec, distance, segLength float64) v.Vec {
+	if segLength == 0 {
+		return a
+	}
+	return a.Lerp(b, min(max(distance/segLength, 0), 1))
+}
@@ -0,0 +1,280 @@
+package path
+
+import (
+	"image"
+	"math"
+
+	"github.com/setanarut/v"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/math/fixed"
+)
+
+// CapMode constants determines line cap style
+const (
+	ButtCap CapMode = iota
+	SquareCap
+	RoundCap
+	CubicCap
+	QuadraticCap
+)
+
+// JoinMode constants determine how stroke segments bridge the gap at a join
+const (
+	MiterJoin JoinMode = iota
+	RoundJoin
+	BevelJoin
+)
+
+// GapMode constants determine how the gap on the outer side of a join is bridged
+// when the miter limit is exceeded
+const (
+	RoundGap GapMode = iota
+	FlatGap
+	CubicGap
+	QuadraticGap
+)
+
+type (
+	JoinMode uint8
+	CapMode  uint8
+	GapMode  uint8
+)
+
+// DefaultMiterLimit is used when the miter limit is zero
+const DefaultMiterLimit = 3
+
+// StrokeOutline holds the geometry of a stroke, the same settings as gog.StrokeStyle without the color
+type StrokeOutline struct {
+	Width float64
+	Cap   CapMode
+	Join  JoinMode
+	Gap   GapMode
+	// MiterLimit is the miter length limit in half line widths, zero means DefaultMiterLimit
+	MiterLimit float64
+	// Dashes holds alternating dash and gap lengths, nil is a continuous line
+	Dashes     []float64
+	DashOffset float64
+}
+
+// outlineExtent is the largest size a shape is scaled up to before stroking, for sub-pixel precision.
+// rasterx miter joins overflow on much longer segments.
+const outlineExtent = 512
+
+// Outline returns the filled outline of the stroke of s.
+//
+// It is the same outline gog.Context.Stroke fills, so caps, joins and dashes match the drawing.
+// Overlaps are removed, the result is a Compound with NonZero fill rule.
+func (st *StrokeOutline) Outline(s Shape) *Compound {
+	return combine(UnionOp, st.region(s), &region{})
+}
+
+// region returns the raw stroke outline of s, its edges overlap
+func (st *StrokeOutline) region(s Shape) *region {
+	b := &boundsPen{lo: v.Vec{X: math.Inf(1), Y: math.Inf(1)}, hi: v.Vec{X: math.Inf(-1), Y: math.Inf(-1)}}
+	s.Trace(b)
+	r := &region{}
+	if b.lo.X > b.hi.X || st.Width <= 0 {
+		return r
+	}
+	// Stroke at a power of two scale where fixed point coordinates have enough precision
+	extent := max(b.hi.X-b.lo.X, b.hi.Y-b.lo.Y, st.Width)
+	scale := math.Max(1, math.Exp2(math.Floor(math.Log2(outlineExtent/extent))))
+	origin := b.lo.Sub(v.Vec{X: st.Width, Y: st.Width})
+	rec := &outlineScanner{region: r, origin: origin, scale: scale}
+	stroker := rasterx.NewDasher(0, 0, rec)
+	st.SetStroke(stroker, scale)
+	s.Trace(&fixedAdder{adder: stroker, origin: origin, scale: scale})
+	return r
+}
+
+// SetStroke sets the stroke of d, with width and dashes multiplied by scale.
+//
+// gog.Context.Stroke and Outline both configure their stroker with it, so drawn strokes
+// and their outlines stay the same.
+func (st *StrokeOutline) SetStroke(d *rasterx.Dasher, scale float64) {
+	miter := st.MiterLimit
+	if miter <= 0 {
+		miter = DefaultMiterLimit
+	}
+	var dashes []float64
+	if st.Dashes != nil {
+		dashes = make([]float64, len(st.Dashes))
+		for i, dash := range st.Dashes {
+			dashes[i] = dash * scale
+		}
+	}
+	c := st.Cap.capFunc()
+	d.SetStroke(fixed.Int26_6(st.Width*scale*64), fixed.Int26_6(miter*64), c, c,
+		st.Gap.gapFunc(), st.Join.joinMode(), dashes, dashPhase(dashes, st.DashOffset*scale))
+}
+
+// dashPhase returns offset wrapped into one period of the dash pattern like stroke-dashoffset.
+// rasterx only walks forward from the start of the pattern, so negative offsets would be ignored
+// and large ones take a step for every dash they pass.
+func dashPhase(dashes []float64, offset float64) float64 {
+	period := 0.0
+	for _, dash := range dashes {
+		period += max(dash, 0)
+	}
+	// Odd patterns swap dashes and gaps on each repeat
+	if len(dashes)%2 == 1 {
+		period *= 2
+	}
+	if period <= 0 {
+		return 0
+	}
+	offset = math.Mod(offset, period)
+	if offset < 0 {
+		offset += period
+	}
+	return offset
+}
+
+// capFunc returns the rasterx cap function
+func (c CapMode) capFunc() rasterx.CapFunc {
+	switch c {
+	case SquareCap:
+		return rasterx.SquareCap
+	case RoundCap:
+		return rasterx.RoundCap
+	case CubicCap:
+		return rasterx.CubicCap
+	case QuadraticCap:
+		return rasterx.QuadraticCap
+	}
+	return rasterx.ButtCap
+}
+
+// joinMode returns the rasterx join mode
+func (j JoinMode) joinMode() rasterx.JoinMode {
+	switch j {
+	case RoundJoin:
+		return rasterx.Round
+	case BevelJoin:
+		return rasterx.Bevel
+	}
+	return rasterx.Miter
+}
+
+// gapFunc returns the rasterx gap function
+func (g GapMode) gapFunc() rasterx.GapFunc {
+	switch g {
+	case FlatGap:
+		return rasterx.FlatGap
+	case CubicGap:
+		return rasterx.CubicGap
+	case QuadraticGap:
+		return rasterx.QuadraticGap
+	}
+	return rasterx.RoundGap
+}
+
+// boundsPen collects the bounds of all points including control points
+type boundsPen struct {
+	lo, hi v.Vec
+}
+
+func (b *boundsPen) add(pts ...v.Vec) {
+	for _, pt := range pts {
+		b.lo = v.Vec{X: min(b.lo.X, pt.X), Y: min(b.lo.Y, pt.Y)}
+		b.hi = v.Vec{X: max(b.hi.X, pt.X), Y: max(b.hi.Y, pt.Y)}
+	}
+}
+
+func (b *boundsPen) Start(pt v.Vec)                    { b.add(pt) }
+func (b *boundsPen) Line(pt v.Vec)                     { b.add(pt) }
+func (b *boundsPen) QuadBezier(ctrl, pt v.Vec)         { b.add(ctrl, pt) }
+func (b *boundsPen) CubeBezier(ctrl1, ctrl2, pt v.Vec) { b.add(ctrl1, ctrl2, pt) }
+func (b *boundsPen) Stop(bool)                         {}
+
+// fixedAdder sends the outline to a rasterx Adder, moved to origin and scaled
+type fixedAdder struct {
+	adder  rasterx.Adder
+	origin v.Vec
+	scale  float64
+}
+
+func (f *fixedAdder) fixed(pt v.Vec) fixed.Point26_6 {
+	pt = pt.Sub(f.origin).Scale(f.scale)
+	return fixed.Point26_6{X: fixed.Int26_6(math.Round(pt.X * 64)), Y: fixed.Int26_6(math.Round(pt.Y * 64))}
+}
+
+func (f *fixedAdder) Start(pt v.Vec) { f.adder.Start(f.fixed(pt)) }
+func (f *fixedAdder) Line(pt v.Vec)  { f.adder.Line(f.fixed(pt)) }
+func (f *fixedAdder) QuadBezier(ctrl, pt v.Vec) {
+	f.adder.QuadBezier(f.fixed(ctrl), f.fixed(pt))
+}
+func (f *fixedAdder) CubeBezier(ctrl1, ctrl2, pt v.Vec) {
+	f.adder.CubeBezier(f.fixed(ctrl1), f.fixed(ctrl2), f.fixed(pt))
+}
+func (f *fixedAdder) Stop(closed bool) { f.adder.Stop(closed) }
+
+// outlineScanner is a rasterx.Scanner that records the lines it receives as region edges
+type outlineScanner struct {
+	region *region
+	origin v.Vec
+	scale  float64
+	last   v.Vec
+}
+
+func (o *outlineScanner) point(p fixed.Point26_6) v.Vec {
+	return v.Vec{X: float64(p.X) / 64, Y: float64(p.Y) / 64}.DivS(o.scale).Add(o.origin)
+}
+
+func (o *outlineScanner) Start(a fixed.Point26_6) {
+	o.last = o.point(a)
+}
+
+func (o *outlineScanner) Line(b fixed.Point26_6) {
+	pt := o.point(b)
+	o.region.addEdge(o.last, pt)
+	o.last = pt
+}
+
+func (o *outlineScanner) Draw()                              {}
+func (o *outlineScanner) GetPathExtent() fixed.Rectangle26_6 { return fixed.Rectangle26_6{} }
+func (o *outlineScanner) SetBounds(w, h int)                 {}
+func (o *outlineScanner) SetColor(color interface{})         {}
+func (o *outlineScanner) SetWinding(useNonZeroWinding bool)  {}
+func (o *outlineScanner) Clear()                             {}
+func (o *outlineScanner) SetClip(rect image.Rectangle)       {}
+
+// Offset returns the outline of the Path grown by distance, negative distances shrink it.
+//
+// The Path is treated as closed, like when it is filled. Corners are shaped by join,
+// miterLimit is in multiples of distance and zero means DefaultMiterLimit.
+// Parts thinner than twice a negative distance disappear, so the result may have several subpaths.
+func (p *Path) Offset(distance float64, join JoinMode, miterLimit float64) *Compound {
+	closed := p.Clone()
+	if closed.Len() > 2 {
+		closed.Close()
+	}
+	return offset(closed, distance, join, miterLimit)
+}
+
+// Offset returns the outline of the Compound grown by distance, negative distances shrink it.
+//
+// See Path.Offset, holes shrink when the Compound grows.
+func (c *Compound) Offset(distance float64, join JoinMode, miterLimit float64) *Compound {
+	closed := c.Clone()
+	for _, p := range closed.Paths {
+		if p.Len() > 2 {
+			p.Close()
+		}
+	}
+	return offset(closed, distance, join, miterLimit)
+}
+
+// offset combines the shape with its stroke of width 2*|distance|
+func offset(s Shape, distance float64, join JoinMode, miterLimit float64) *Compound {
+	gap := FlatGap
+	if join == RoundJoin {
+		gap = RoundGap
+	}
+	st := &StrokeOutline{Width: 2 * math.Abs(distance), Join: join, Gap: gap, MiterLimit: miterLimit}
+	op := UnionOp
+	if distance < 0 {
+		op = DifferenceOp
+	}
+	return combine(op, newRegion(s), st.region(s))
+}
@@ -0,0 +1,92 @@
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Nearest is a draw.Drawer that maps every pixel to the closest palette color without dithering.
+//
+// Unlike draw.Src it caches lookups, which is much faster for large palettes.
+var Nearest draw.Drawer = ditherer{}
+
+// Ordered is a draw.Drawer that dithers with an 8×8 Bayer threshold matrix.
+//
+// The pattern is stable between frames, so animations do not flicker like
+// error diffusion dithering.
+var Ordered draw.Drawer = ditherer{ordered: true}
+
+// bayer8 is the 8×8 Bayer threshold matrix
+var bayer8 = [8][8]uint8{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+type ditherer struct {
+	ordered bool
+}
+
+// Draw maps src to the palette of dst, dst must be an *image.Paletted.
+// Other destination images are drawn with draw.Src.
+func (d ditherer) Draw(dst draw.Image, r image.Rectangle, src image.Image, sp image.Point) {
+	pm, ok := dst.(*image.Paletted)
+	if !ok || len(pm.Palette) == 0 {
+		draw.Draw(dst, r, src, sp, draw.Src)
+		return
+	}
+	r = r.Intersect(dst.Bounds())
+	lookup := newPaletteLookup(pm.Palette)
+	// Threshold amplitude follows the average distance of palette colors
+	spread := 255 / math.Cbrt(float64(len(pm.Palette)))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, _ := src.At(sp.X+x-r.Min.X, sp.Y+y-r.Min.Y).RGBA()
+			rgb := [3]int{int(cr >> 8), int(cg >> 8), int(cb >> 8)}
+			if d.ordered {
+				t := int((float64(bayer8[y&7][x&7])/64 - 0.5) * spread)
+				for c := range rgb {
+					rgb[c] = min(255, max(0, rgb[c]+t))
+				}
+			}
+			pm.Pix[pm.PixOffset(x, y)] = lookup.index(rgb)
+		}
+	}
+}
+
+// paletteLookup finds the closest palette color, results are cached by color
+type paletteLookup struct {
+	palette [][3]int
+	cache   map[[3]int]uint8
+}
+
+func newPaletteLookup(p color.Palette) *paletteLookup {
+	l := &paletteLookup{cache: make(map[[3]int]uint8)}
+	for _, c := range p {
+		r, g, b, _ := c.RGBA()
+		l.palette = append(l.palette, [3]int{int(r >> 8), int(g >> 8), int(b >> 8)})
+	}
+	return l
+}
+
+func (l *paletteLookup) index(rgb [3]int) uint8 {
+	if i, ok := l.cache[rgb]; ok {
+		return i
+	}
+	best, bestDist := 0, math.MaxInt
+	for i, p := range l.palette {
+		dr, dg, db := rgb[0]-p[0], rgb[1]-p[1], rgb[2]-p[2]
+		if d := dr*dr + dg*dg + db*db; d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	l.cache[rgb] = uint8(best)
+	return uint8(best)
+}
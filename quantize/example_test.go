@@ -0,0 +1,25 @@
+package quantize_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/setanarut/gog/v2/quantize"
+)
+
+// Reduces a two tone image to a two color palette and maps it without dithering
+func ExampleMedianCut() {
+	m := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	draw.Draw(m, image.Rect(0, 0, 2, 1), image.NewUniform(color.RGBA{255, 0, 0, 255}), image.Point{}, draw.Src)
+	draw.Draw(m, image.Rect(2, 0, 4, 1), image.NewUniform(color.RGBA{0, 0, 255, 255}), image.Point{}, draw.Src)
+	palette := quantize.MedianCut{}.Quantize(make(color.Palette, 0, 2), m)
+	pm := image.NewPaletted(m.Bounds(), palette)
+	quantize.Nearest.Draw(pm, pm.Bounds(), m, image.Point{})
+	fmt.Println(palette)
+	fmt.Println(pm.Pix)
+	// Output:
+	// [{0 0 255 255} {255 0 0 255}]
+	// [1 1 0 0]
+}
@@ -0,0 +1,106 @@
+package quantize
+
+import (
+	"image"
+	"image/color"
+	"slices"
+)
+
+// MedianCut is a draw.Quantizer that repeatedly splits the color box with the most pixels
+// at the median of its widest channel. It preserves large flat areas well.
+type MedianCut struct{}
+
+// Quantize appends up to cap(p)-len(p) colors of m to p
+func (MedianCut) Quantize(p color.Palette, m image.Image) color.Palette {
+	n := available(p)
+	buckets := histogram(m)
+	if n == 0 || len(buckets) == 0 {
+		return p
+	}
+	boxes := []colorBox{newColorBox(buckets)}
+	for len(boxes) < n {
+		// Split the box with the most pixels that can still be split
+		best := -1
+		for i, b := range boxes {
+			if len(b.buckets) > 1 && (best < 0 || b.count > boxes[best].count) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		lo, hi := boxes[best].split()
+		boxes[best] = lo
+		boxes = append(boxes, hi)
+	}
+	for _, b := range boxes {
+		p = append(p, b.mean())
+	}
+	return p
+}
+
+// colorBox is a set of histogram buckets
+type colorBox struct {
+	buckets []bucket
+	count   uint64
+}
+
+func newColorBox(buckets []bucket) colorBox {
+	b := colorBox{buckets: buckets}
+	for _, c := range buckets {
+		b.count += c.count
+	}
+	return b
+}
+
+// widest returns the channel with the largest range
+func (b *colorBox) widest() int {
+	var lo, hi [3]uint8
+	for c := range 3 {
+		lo[c], hi[c] = 255, 0
+	}
+	for i := range b.buckets {
+		for c := range 3 {
+			v := b.buckets[i].channel(c)
+			lo[c] = min(lo[c], v)
+			hi[c] = max(hi[c], v)
+		}
+	}
+	widest := 0
+	for c := 1; c < 3; c++ {
+		if hi[c]-lo[c] > hi[widest]-lo[widest] {
+			widest = c
+		}
+	}
+	return widest
+}
+
+// split divides the box at the pixel median of its widest channel, both halves are non-empty
+func (b *colorBox) split() (colorBox, colorBox) {
+	c := b.widest()
+	slices.SortFunc(b.buckets, func(x, y bucket) int {
+		return int(x.channel(c)) - int(y.channel(c))
+	})
+	half := b.count / 2
+	sum := uint64(0)
+	i := 1
+	for ; i < len(b.buckets)-1; i++ {
+		sum += b.buckets[i-1].count
+		if sum >= half {
+			break
+		}
+	}
+	return newColorBox(b.buckets[:i]), newColorBox(b.buckets[i:])
+}
+
+// mean returns the pixel weighted average color of the box
+func (b *colorBox) mean() color.Color {
+	var sum bucket
+	for _, c := range b.buckets {
+		sum.r += c.r
+		sum.g += c.g
+		sum.b += c.b
+		sum.count += c.count
+	}
+	return sum.mean()
+}
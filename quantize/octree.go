@@ -0,0 +1,136 @@
+package quantize
+
+import (
+	"cmp"
+	"image"
+	"image/color"
+	"slices"
+)
+
+// octreeDepth is the depth of the leaves, it matches the histogram precision
+const octreeDepth = histogramBits
+
+// Octree is a draw.Quantizer that builds a color octree and merges the leaves with the
+// fewest pixels until the palette fits. It keeps small areas of distinct colors.
+type Octree struct{}
+
+// Quantize appends up to cap(p)-len(p) colors of m to p
+func (Octree) Quantize(p color.Palette, m image.Image) color.Palette {
+	n := available(p)
+	buckets := histogram(m)
+	if n == 0 || len(buckets) == 0 {
+		return p
+	}
+	root := new(octreeNode)
+	leaves := 0
+	// levels holds the inner nodes of every depth, the deepest are merged first
+	levels := make([][]*octreeNode, octreeDepth)
+	for i := range buckets {
+		b := &buckets[i]
+		node := root
+		for depth := range octreeDepth {
+			shift := histogramBits - 1 - depth
+			child := int(b.channel(0)>>shift&1)<<2 | int(b.channel(1)>>shift&1)<<1 | int(b.channel(2)>>shift&1)
+			if node.children[child] == nil {
+				node.children[child] = new(octreeNode)
+				if depth == octreeDepth-1 {
+					leaves++
+				} else {
+					levels[depth+1] = append(levels[depth+1], node.children[child])
+				}
+			}
+			node = node.children[child]
+		}
+		node.sum.r += b.r
+		node.sum.g += b.g
+		node.sum.b += b.b
+		node.sum.count += b.count
+	}
+	levels[0] = []*octreeNode{root}
+	for depth := octreeDepth - 1; depth >= 0 && leaves > n; depth-- {
+		nodes := levels[depth]
+		for _, node := range nodes {
+			node.total = node.pixels()
+		}
+		// Merge the least used nodes first
+		sortNodes(nodes)
+		for _, node := range nodes {
+			if leaves <= n {
+				break
+			}
+			leaves -= node.merge() - 1
+		}
+	}
+	return root.appendColors(p)
+}
+
+type octreeNode struct {
+	children [8]*octreeNode
+	// sum holds the colors of a leaf
+	sum bucket
+	// total pixel count of the subtree, used to order merges
+	total uint64
+}
+
+// isLeaf reports whether the node has no children
+func (o *octreeNode) isLeaf() bool {
+	for _, c := range o.children {
+		if c != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// pixels returns the number of pixels in the subtree
+func (o *octreeNode) pixels() uint64 {
+	total := o.sum.count
+	for _, c := range o.children {
+		if c != nil {
+			total += c.pixels()
+		}
+	}
+	return total
+}
+
+// merge folds the leaves below the node into it and returns the number of removed leaves
+func (o *octreeNode) merge() int {
+	removed := 0
+	for i, c := range o.children {
+		if c == nil {
+			continue
+		}
+		if !c.isLeaf() {
+			removed += c.merge() - 1
+		}
+		o.sum.r += c.sum.r
+		o.sum.g += c.sum.g
+		o.sum.b += c.sum.b
+		o.sum.count += c.sum.count
+		o.children[i] = nil
+		removed++
+	}
+	return removed
+}
+
+// appendColors appends the mean colors of the leaves to p
+func (o *octreeNode) appendColors(p color.Palette) color.Palette {
+	if o.isLeaf() {
+		if o.sum.count > 0 {
+			p = append(p, o.sum.mean())
+		}
+		return p
+	}
+	for _, c := range o.children {
+		if c != nil {
+			p = c.appendColors(p)
+		}
+	}
+	return p
+}
+
+func sortNodes(nodes []*octreeNode) {
+	slices.SortFunc(nodes, func(a, b *octreeNode) int {
+		return cmp.Compare(a.total, b.total)
+	})
+}
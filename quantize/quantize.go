@@ -0,0 +1,133 @@
+// Package quantize reduces images to small palettes for indexed formats such as GIF.
+//
+// MedianCut and Octree implement draw.Quantizer, Nearest and Ordered implement draw.Drawer
+// and map images to a palette without and with ordered dithering.
+// Use draw.FloydSteinberg from the standard library for error diffusion dithering.
+//
+// Alpha is ignored, colors are taken as premultiplied, which is the same as drawing over black.
+package quantize
+
+import (
+	"image"
+	"image/color"
+)
+
+// histogramBits is the precision of each channel in the color histogram
+const histogramBits = 5
+
+// bucket is a histogram cell holding the count and the channel sums of its colors
+type bucket struct {
+	r, g, b uint64
+	count   uint64
+}
+
+// mean returns the average color of the bucket
+func (b *bucket) mean() color.RGBA {
+	return color.RGBA{
+		R: uint8(b.r / b.count),
+		G: uint8(b.g / b.count),
+		B: uint8(b.b / b.count),
+		A: 255,
+	}
+}
+
+// channel returns the histogram coordinate of channel 0=red 1=green 2=blue
+func (b *bucket) channel(c int) uint8 {
+	switch c {
+	case 0:
+		return uint8(b.r/b.count) >> (8 - histogramBits)
+	case 1:
+		return uint8(b.g/b.count) >> (8 - histogramBits)
+	}
+	return uint8(b.b/b.count) >> (8 - histogramBits)
+}
+
+// histogram returns the non-empty buckets of the colors of m
+func histogram(m image.Image) []bucket {
+	cells := make([]bucket, 1<<(3*histogramBits))
+	if s, ok := m.(*frameStack); ok {
+		for _, f := range s.frames {
+			accumulate(cells, f)
+		}
+	} else {
+		accumulate(cells, m)
+	}
+	buckets := cells[:0]
+	for _, c := range cells {
+		if c.count > 0 {
+			buckets = append(buckets, c)
+		}
+	}
+	return buckets
+}
+
+// accumulate adds the colors of m to the histogram cells
+func accumulate(cells []bucket, m image.Image) {
+	add := func(r, g, b uint8) {
+		const s = 8 - histogramBits
+		c := &cells[int(r>>s)<<(2*histogramBits)|int(g>>s)<<histogramBits|int(b>>s)]
+		c.r += uint64(r)
+		c.g += uint64(g)
+		c.b += uint64(b)
+		c.count++
+	}
+	bounds := m.Bounds()
+	if rgba, ok := m.(*image.RGBA); ok {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			i := rgba.PixOffset(bounds.Min.X, y)
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				add(rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2])
+				i += 4
+			}
+		}
+		return
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := m.At(x, y).RGBA()
+			add(uint8(r>>8), uint8(g>>8), uint8(b>>8))
+		}
+	}
+}
+
+// Frames returns an image that stacks frames vertically without copying them.
+//
+// Quantize it to build one palette shared by all frames of an animation.
+// Frames must have the same size.
+func Frames(frames []image.Image) image.Image {
+	return &frameStack{frames}
+}
+
+type frameStack struct {
+	frames []image.Image
+}
+
+func (s *frameStack) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (s *frameStack) Bounds() image.Rectangle {
+	if len(s.frames) == 0 {
+		return image.Rectangle{}
+	}
+	size := s.frames[0].Bounds().Size()
+	return image.Rect(0, 0, size.X, size.Y*len(s.frames))
+}
+
+func (s *frameStack) At(x, y int) color.Color {
+	if len(s.frames) == 0 {
+		return color.Transparent
+	}
+	b := s.frames[0].Bounds()
+	i := y / b.Dy()
+	if y < 0 || i >= len(s.frames) {
+		return color.Transparent
+	}
+	f := s.frames[i]
+	return f.At(f.Bounds().Min.X+x, f.Bounds().Min.Y+y%b.Dy())
+}
+
+// available returns the number of colors a draw.Quantizer may append to p
+func available(p color.Palette) int {
+	return max(0, cap(p)-len(p))
+}
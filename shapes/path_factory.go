@@ -26,6 +26,23 @@ func CubicBezier(x0, y0, x1, y1, x2, y2, x3, y3 float64, samples int) *path.Path
 	return p
 }
 
+// CubicBezierCurve returns a cubic-bezier path.Curve.
+//
+// Unlike CubicBezier, the curve is not sampled and stays smooth at any scale.
+func CubicBezierCurve(x0, y0, x1, y1, x2, y2, x3, y3 float64) *path.Curve {
+	c := path.NewCurve(v.Vec{X: x0, Y: y0})
+	c.CubeTo(v.Vec{X: x1, Y: y1}, v.Vec{X: x2, Y: y2}, v.Vec{X: x3, Y: y3})
+	return c.SetAnchorToCentroid()
+}
+
+// Arc returns a circular arc path.Curve.
+//
+// startAngle and sweep are in radians, positive sweep turns from +X towards +Y.
+func Arc(origin v.Vec, radius, startAngle, sweep float64) *path.Curve {
+	c := path.NewCurve(utils.PointOnCircle(origin, radius, startAngle))
+	return c.ArcTo(origin, radius, radius, 0, sweep).SetAnchor(origin)
+}
+
 // Rect returns a rectangle-shaped path.Path.
 func Rect(topLeft v.Vec, w, h float64) *path.Path {
 	Sq := path.NewPath([]v.Vec{{}, {w, 0}, {w, h}, {0, h}})
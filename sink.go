@@ -0,0 +1,159 @@
+package gog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/setanarut/gog/v2/utils"
+)
+
+var errFrameSize = errors.New("gog: frame size differs from the first frame")
+
+// FrameSink receives animation frames as they are rendered.
+//
+// Unlike AppendAnimationFrame, frames are encoded immediately and not kept in memory.
+// Use Context.WriteFrame to send the canvas to a sink.
+type FrameSink interface {
+	// WriteFrame encodes img, it must not keep img after returning
+	WriteFrame(img image.Image) error
+	// Close finishes the output, it does not close the underlying writer
+	Close() error
+}
+
+// WriteFrame writes the current canvas to sink
+func (ctx *Context) WriteFrame(sink FrameSink) error {
+	return sink.WriteFrame(ctx.surface)
+}
+
+// PNGSequenceSink saves every frame as a numbered PNG file
+type PNGSequenceSink struct {
+	pattern string
+	index   int
+}
+
+// NewPNGSequenceSink returns a sink that saves frames to files named by pattern,
+// which is formatted with the frame index, e.g. "frames/frame_%04d.png".
+func NewPNGSequenceSink(pattern string) *PNGSequenceSink {
+	return &PNGSequenceSink{pattern: pattern}
+}
+
+// WriteFrame saves img as the next file of the sequence
+func (s *PNGSequenceSink) WriteFrame(img image.Image) error {
+	err := utils.WritePNG(fmt.Sprintf(s.pattern, s.index), img)
+	s.index++
+	return err
+}
+
+// Close does nothing, every frame is already saved
+func (s *PNGSequenceSink) Close() error {
+	return nil
+}
+
+// Y4MSink streams frames as YUV4MPEG2 video with 4:2:0 chroma, for piping into video encoders
+//
+//	ffmpeg -i - -c:v libx264 out.mp4
+type Y4MSink struct {
+	w       *bufio.Writer
+	fps     int
+	size    image.Point
+	started bool
+	y, u, v []uint8
+}
+
+// NewY4MSink returns a Y4M sink writing to w at fps frames per second
+func NewY4MSink(w io.Writer, fps int) *Y4MSink {
+	return &Y4MSink{w: bufio.NewWriter(w), fps: fps}
+}
+
+// WriteFrame converts img to full range YCbCr and writes it
+func (s *Y4MSink) WriteFrame(img image.Image) error {
+	b := img.Bounds()
+	if !s.started {
+		s.size = b.Size()
+		fmt.Fprintf(s.w, "YUV4MPEG2 W%d H%d F%d:1 Ip A1:1 C420jpeg XCOLORRANGE=FULL\n", s.size.X, s.size.Y, s.fps)
+		s.started = true
+	} else if b.Size() != s.size {
+		return errFrameSize
+	}
+	cw, ch := (s.size.X+1)/2, (s.size.Y+1)/2
+	s.y = resize(s.y, s.size.X*s.size.Y)
+	s.u = resize(s.u, cw*ch)
+	s.v = resize(s.v, cw*ch)
+	for cy := range ch {
+		for cx := range cw {
+			// Chroma is the average of the 2×2 block
+			var sumCb, sumCr, n int
+			for dy := range 2 {
+				for dx := range 2 {
+					x, y := cx*2+dx, cy*2+dy
+					if x >= s.size.X || y >= s.size.Y {
+						continue
+					}
+					r, g, bl := rgb8(img.At(b.Min.X+x, b.Min.Y+y))
+					yy, cb, cr := color.RGBToYCbCr(r, g, bl)
+					s.y[y*s.size.X+x] = yy
+					sumCb += int(cb)
+					sumCr += int(cr)
+					n++
+				}
+			}
+			s.u[cy*cw+cx] = uint8(sumCb / n)
+			s.v[cy*cw+cx] = uint8(sumCr / n)
+		}
+	}
+	s.w.WriteString("FRAME\n")
+	s.w.Write(s.y)
+	s.w.Write(s.u)
+	_, err := s.w.Write(s.v)
+	return err
+}
+
+// Close flushes buffered data
+func (s *Y4MSink) Close() error {
+	return s.w.Flush()
+}
+
+// PPMSink streams frames as concatenated binary PPM images, for piping into video encoders
+//
+//	ffmpeg -f image2pipe -c:v ppm -i - out.mp4
+type PPMSink struct {
+	w   *bufio.Writer
+	row []uint8
+}
+
+// NewPPMSink returns a PPM sink writing to w
+func NewPPMSink(w io.Writer) *PPMSink {
+	return &PPMSink{w: bufio.NewWriter(w)}
+}
+
+// WriteFrame writes img as a P6 image, alpha is dropped
+func (s *PPMSink) WriteFrame(img image.Image) error {
+	b := img.Bounds()
+	fmt.Fprintf(s.w, "P6\n%d %d\n255\n", b.Dx(), b.Dy())
+	s.row = resize(s.row, b.Dx()*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := (x - b.Min.X) * 3
+			s.row[i], s.row[i+1], s.row[i+2] = rgb8(img.At(x, y))
+		}
+		if _, err := s.w.Write(s.row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes buffered data
+func (s *PPMSink) Close() error {
+	return s.w.Flush()
+}
+
+// rgb8 returns the 8-bit premultiplied color channels, which is the color drawn over black
+func rgb8(c color.Color) (uint8, uint8, uint8) {
+	r, g, b, _ := c.RGBA()
+	return uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)
+}
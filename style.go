@@ -2,30 +2,47 @@ package gog
 
 import (
 	"image/color"
+
+	"github.com/setanarut/gog/v2/path"
 )
 
 // CapMode constants determines line cap style
 const (
-	ButtCap CapMode = iota
-	SquareCap
-	RoundCap
-	CubicCap
-	QuadraticCap
+	ButtCap      = path.ButtCap
+	SquareCap    = path.SquareCap
+	RoundCap     = path.RoundCap
+	CubicCap     = path.CubicCap
+	QuadraticCap = path.QuadraticCap
 )
 
 // JoinMode constants determine how stroke segments bridge the gap at a join
 const (
-	MiterJoin JoinMode = iota
-	RoundJoin
-	BevelJoin
+	MiterJoin = path.MiterJoin
+	RoundJoin = path.RoundJoin
+	BevelJoin = path.BevelJoin
+)
+
+// GapMode constants determine how the gap on the outer side of a join is bridged
+// when the miter limit is exceeded
+const (
+	RoundGap     = path.RoundGap
+	FlatGap      = path.FlatGap
+	CubicGap     = path.CubicGap
+	QuadraticGap = path.QuadraticGap
 )
 
+// Stroke modes are defined in the path package, so outlines can be built without a Context
 type (
-	JoinMode uint8
-	CapMode  uint8
-	DrawMode uint8
+	JoinMode = path.JoinMode
+	CapMode  = path.CapMode
+	GapMode  = path.GapMode
 )
 
+type DrawMode uint8
+
+// defaultMiterLimit is used when StrokeStyle.MiterLimit is zero
+const defaultMiterLimit = path.DefaultMiterLimit
+
 var debugStyle *StrokeStyle = &StrokeStyle{
 	// FillColor:   color.RGBA{255, 255, 0, 255}, //Yellow
 	Color:     color.RGBA{255, 0, 255, 255}, //Magenta
@@ -46,6 +63,19 @@ type StrokeStyle struct {
 	//
 	// 0=MiterJoin 1=RoundJoin 2=BevelJoin
 	Join JoinMode
+	// Gap bridges the outer side of joins exceeding MiterLimit
+	//
+	// 0=RoundGap 1=FlatGap 2=CubicGap 3=QuadraticGap
+	Gap GapMode
+	// MiterLimit is the miter length limit in half line widths, zero means 3
+	MiterLimit float64
+	// Dashes holds alternating dash and gap lengths. Caps are applied to each dash.
+	// Nil draws a continuous line.
+	Dashes []float64
+	// DashOffset shifts the dash pattern along the path.
+	// Change it between animation frames for a marching ants effect.
+	// It wraps around the pattern like stroke-dashoffset, so it can also decrease.
+	DashOffset float64
 }
 
 func (s *StrokeStyle) SetColor(c color.Color) *StrokeStyle {
@@ -57,6 +87,26 @@ func (s *StrokeStyle) SetLineWidth(w float64) *StrokeStyle {
 	return s
 }
 
+// SetDashes sets dash pattern, call without arguments for a continuous line
+func (s *StrokeStyle) SetDashes(dashes ...float64) *StrokeStyle {
+	s.Dashes = dashes
+	return s
+}
+
+// SetDashOffset sets the offset into the dash pattern
+func (s *StrokeStyle) SetDashOffset(offset float64) *StrokeStyle {
+	s.DashOffset = offset
+	return s
+}
+
+// miterLimit returns MiterLimit or the default
+func (s *StrokeStyle) miterLimit() float64 {
+	if s.MiterLimit <= 0 {
+		return defaultMiterLimit
+	}
+	return s.MiterLimit
+}
+
 // NewStyle shorthand for create Style{}
 func NewStrokeStyle(fillColor, strokeColor color.Color, lineWidth float64, cap CapMode, join JoinMode) *StrokeStyle {
 	return &StrokeStyle{
@@ -76,3 +126,24 @@ func DefaultStrokeStyle() *StrokeStyle {
 		Join:      MiterJoin,
 	}
 }
+
+// outline returns the stroke geometry of the style
+func (s *StrokeStyle) outline() *path.StrokeOutline {
+	return &path.StrokeOutline{
+		Width:      s.LineWidth,
+		Cap:        s.Cap,
+		Join:       s.Join,
+		Gap:        s.Gap,
+		MiterLimit: s.MiterLimit,
+		Dashes:     s.Dashes,
+		DashOffset: s.DashOffset,
+	}
+}
+
+// StrokeToPath returns the filled outline of the stroke of s with strokeStyle.
+//
+// Filling the result looks like Context.Stroke without a transform. The outline can be
+// used with boolean operations, offset further or sent to a plotter.
+func StrokeToPath(s path.Shape, strokeStyle *StrokeStyle) *path.Compound {
+	return strokeStyle.outline().Outline(s)
+}
@@ -0,0 +1,465 @@
+package gog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/setanarut/gog/v2/affine"
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/v"
+)
+
+// Canvas is implemented by every drawing target of gog.
+//
+// Code written against Canvas can render either to a raster Context or to a vector SVGContext.
+// Both contexts also have Push, Pop, Translate, Rotate, Scale, Skew, SetTransform, Clip, ResetClip
+// and Clear. They return their own context for chaining, so they are not part of Canvas.
+type Canvas interface {
+	Fill(s path.Shape, fillColor color.Color)
+	Stroke(s path.Shape, strokeStyle *StrokeStyle)
+	DrawImage(img image.Image, pos v.Vec)
+	Transform() affine.Matrix
+}
+
+var (
+	_ Canvas = (*Context)(nil)
+	_ Canvas = (*SVGContext)(nil)
+)
+
+// SVGContext records drawing calls as SVG elements.
+//
+// It mirrors the drawing methods of Context, so the same drawing code can produce a vector version of a piece.
+// Shapes are written with the current transform applied to their points, so line widths and dashes
+// are scaled like in Context. Clips become <clipPath> elements and images are embedded as PNG.
+type SVGContext struct {
+	// Center point of Canvas
+	Center v.Vec
+
+	width, height int
+	defs          []string
+	elements      []string
+	state         svgState
+	stack         []svgState
+}
+
+// svgState is the part of the SVGContext saved by Push and restored by Pop
+type svgState struct {
+	transform affine.Matrix
+	// clip is the id of the current <clipPath>, empty means no clip
+	clip string
+}
+
+// NewSVGContext returns a new SVG drawing context.
+func NewSVGContext(width, height int) *SVGContext {
+	svg := new(SVGContext)
+	svg.width = width
+	svg.height = height
+	svg.Center = v.Vec{X: float64(width) / 2, Y: float64(height) / 2}
+	svg.state.transform = affine.Identity
+	return svg
+}
+
+// Push saves the current transform and clip, Pop restores them
+func (svg *SVGContext) Push() *SVGContext {
+	svg.stack = append(svg.stack, svg.state)
+	return svg
+}
+
+// Pop restores the transform and clip saved by the last Push. Pop without a matching Push is ignored.
+func (svg *SVGContext) Pop() *SVGContext {
+	if n := len(svg.stack); n > 0 {
+		svg.state = svg.stack[n-1]
+		svg.stack = svg.stack[:n-1]
+	}
+	return svg
+}
+
+// Translate moves the origin of the drawing coordinates
+func (svg *SVGContext) Translate(x, y float64) *SVGContext {
+	svg.state.transform = svg.state.transform.Translate(x, y)
+	return svg
+}
+
+// Rotate rotates the drawing coordinates about the origin, angle in radians
+func (svg *SVGContext) Rotate(angle float64) *SVGContext {
+	svg.state.transform = svg.state.transform.Rotate(angle)
+	return svg
+}
+
+// Scale scales the drawing coordinates about the origin
+func (svg *SVGContext) Scale(x, y float64) *SVGContext {
+	svg.state.transform = svg.state.transform.Scale(x, y)
+	return svg
+}
+
+// Skew skews the drawing coordinates, angles in radians
+func (svg *SVGContext) Skew(angleX, angleY float64) *SVGContext {
+	svg.state.transform = svg.state.transform.Skew(angleX, angleY)
+	return svg
+}
+
+// SetTransform replaces the current transform. Use affine.Identity to reset it.
+func (svg *SVGContext) SetTransform(m affine.Matrix) *SVGContext {
+	svg.state.transform = m
+	return svg
+}
+
+// Transform returns the current transform
+func (svg *SVGContext) Transform() affine.Matrix {
+	return svg.state.transform
+}
+
+// Clip restricts drawing to the inside of shape, intersected with the current clip.
+//
+// The shape is written as a <clipPath> with the current transform and its fill rule.
+// Use Push and Pop to restore the previous clip.
+func (svg *SVGContext) Clip(s path.Shape) *SVGContext {
+	id := fmt.Sprintf("clip%d", len(svg.defs)+1)
+	rule := ""
+	if fillRule(s) == path.EvenOdd {
+		rule = ` clip-rule="evenodd"`
+	}
+	svg.defs = append(svg.defs, fmt.Sprintf(`<clipPath id="%s"%s><path d="%s"%s/></clipPath>`,
+		id, svg.clipAttr(), svgPathData(s, svg.state.transform), rule))
+	svg.state.clip = id
+	return svg
+}
+
+// ResetClip removes the clip, drawing affects the whole canvas again
+func (svg *SVGContext) ResetClip() *SVGContext {
+	svg.state.clip = ""
+	return svg
+}
+
+// Fill records a <path> element filled with fillColor
+//
+// SVG has no conic gradient, a ConicGradient or another Pattern that is not a linear or radial
+// gradient is rasterized at canvas resolution and embedded as an image <pattern>.
+func (svg *SVGContext) Fill(s path.Shape, fillColor color.Color) {
+	d := svgPathData(s, svg.state.transform)
+	if d == "" {
+		return
+	}
+	rule := ""
+	if fillRule(s) == path.EvenOdd {
+		rule = ` fill-rule="evenodd"`
+	}
+	svg.elements = append(svg.elements, fmt.Sprintf(`<path d="%s" %s%s%s/>`,
+		d, svg.paint("fill", fillColor), rule, svg.clipAttr()))
+}
+
+// Stroke records a <path> element stroked with StrokeStyle
+//
+// Line width and dashes are scaled by the average scale factor of the transform like in Context.
+func (svg *SVGContext) Stroke(s path.Shape, strokeStyle *StrokeStyle) {
+	d := svgPathData(s, svg.state.transform)
+	if d == "" {
+		return
+	}
+	scale := math.Sqrt(math.Abs(svg.state.transform.Det()))
+	dash := ""
+	if len(strokeStyle.Dashes) > 0 {
+		nums := make([]string, len(strokeStyle.Dashes))
+		for i, d := range strokeStyle.Dashes {
+			nums[i] = svgNumber(d * scale)
+		}
+		dash = fmt.Sprintf(` stroke-dasharray="%s"`, strings.Join(nums, " "))
+		if strokeStyle.DashOffset != 0 {
+			dash += fmt.Sprintf(` stroke-dashoffset="%s"`, svgNumber(strokeStyle.DashOffset*scale))
+		}
+	}
+	svg.elements = append(svg.elements, fmt.Sprintf(
+		`<path d="%s" fill="none" %s stroke-width="%s" stroke-linecap="%s" stroke-linejoin="%s" stroke-miterlimit="%s"%s%s/>`,
+		d,
+		svg.paint("stroke", strokeStyle.Color),
+		svgNumber(strokeStyle.LineWidth*scale),
+		svgLineCap(strokeStyle.Cap),
+		svgLineJoin(strokeStyle.Join),
+		svgNumber(strokeStyle.miterLimit()),
+		dash,
+		svg.clipAttr()))
+}
+
+// DrawImage records an <image> element with img embedded as PNG, its top left corner at pos.
+//
+// The image is placed with the current transform and clip.
+func (svg *SVGContext) DrawImage(img image.Image, pos v.Vec) {
+	b := img.Bounds()
+	m := svg.state.transform.Translate(pos.X, pos.Y)
+	svg.elements = append(svg.elements, fmt.Sprintf(
+		`<image width="%d" height="%d" transform="%s" href="%s"%s/>`,
+		b.Dx(), b.Dy(), svgMatrix(m), svgImageData(img), svg.clipAttr()))
+}
+
+// Clear discards all recorded elements and fills the canvas with color c.
+//
+// If a clip is active, only a <rect> covering the clip region is added.
+func (svg *SVGContext) Clear(c color.Color) *SVGContext {
+	if svg.state.clip != "" {
+		svg.elements = append(svg.elements, fmt.Sprintf(`<rect width="%d" height="%d" %s%s/>`,
+			svg.width, svg.height, svg.paint("fill", c), svg.clipAttr()))
+		return svg
+	}
+	if !svg.clipSaved() {
+		svg.defs = svg.defs[:0]
+	}
+	svg.elements = svg.elements[:0]
+	svg.elements = append(svg.elements, fmt.Sprintf(`<rect width="%d" height="%d" %s/>`,
+		svg.width, svg.height, svg.paint("fill", c)))
+	return svg
+}
+
+// clipAttr returns the clip-path attribute of the current clip
+func (svg *SVGContext) clipAttr() string {
+	if svg.state.clip == "" {
+		return ""
+	}
+	return fmt.Sprintf(` clip-path="url(#%s)"`, svg.state.clip)
+}
+
+// clipSaved reports whether a state saved by Push refers to a <clipPath>, its defs must be kept
+func (svg *SVGContext) clipSaved() bool {
+	for _, st := range svg.stack {
+		if st.clip != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeSVG writes the recorded elements as an SVG document sized to the canvas.
+func (svg *SVGContext) EncodeSVG(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		svg.width, svg.height, svg.width, svg.height)
+	if len(svg.defs) > 0 {
+		b.WriteString("<defs>\n")
+		for _, def := range svg.defs {
+			b.WriteString(def)
+			b.WriteByte('\n')
+		}
+		b.WriteString("</defs>\n")
+	}
+	for _, element := range svg.elements {
+		b.WriteString(element)
+		b.WriteByte('\n')
+	}
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
+
+// SaveSVG saves the recorded elements as an SVG file.
+func (svg *SVGContext) SaveSVG(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := svg.EncodeSVG(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// svgPathData returns the SVG "d" attribute of the shape transformed with m.
+func svgPathData(s path.Shape, m affine.Matrix) string {
+	pen := &svgPen{m: m}
+	s.Trace(pen)
+	return pen.sb.String()
+}
+
+// svgPen writes path commands transformed with m as SVG path data.
+//
+// A closing line back to the start point is replaced by "Z".
+type svgPen struct {
+	sb         strings.Builder
+	m          affine.Matrix
+	start      v.Vec
+	pending    v.Vec
+	hasPending bool
+}
+
+func (p *svgPen) Start(pt v.Vec) {
+	if p.sb.Len() > 0 {
+		p.sb.WriteByte(' ')
+	}
+	p.sb.WriteByte('M')
+	p.writePoint(pt)
+	p.start = pt
+	p.hasPending = false
+}
+
+func (p *svgPen) Line(pt v.Vec) {
+	p.flush()
+	p.pending = pt
+	p.hasPending = true
+}
+
+func (p *svgPen) QuadBezier(ctrl, pt v.Vec) {
+	p.flush()
+	p.sb.WriteString(" Q")
+	p.writePoint(ctrl)
+	p.sb.WriteByte(' ')
+	p.writePoint(pt)
+}
+
+func (p *svgPen) CubeBezier(ctrl1, ctrl2, pt v.Vec) {
+	p.flush()
+	p.sb.WriteString(" C")
+	p.writePoint(ctrl1)
+	p.sb.WriteByte(' ')
+	p.writePoint(ctrl2)
+	p.sb.WriteByte(' ')
+	p.writePoint(pt)
+}
+
+func (p *svgPen) Stop(closed bool) {
+	if closed && p.hasPending && p.pending.Dist(p.start) < 0.1 {
+		p.hasPending = false
+	}
+	p.flush()
+	if closed {
+		p.sb.WriteString(" Z")
+	}
+}
+
+func (p *svgPen) flush() {
+	if p.hasPending {
+		p.sb.WriteString(" L")
+		p.writePoint(p.pending)
+		p.hasPending = false
+	}
+}
+
+func (p *svgPen) writePoint(pt v.Vec) {
+	pt = p.m.Apply(pt)
+	p.sb.WriteString(svgNumber(pt.X))
+	p.sb.WriteByte(' ')
+	p.sb.WriteString(svgNumber(pt.Y))
+}
+
+// paint returns the color attribute and its opacity attribute if the color is translucent.
+//
+// Linear and radial gradients are written to <defs> and referenced by id, transformed with the shapes.
+// Other Patterns are sampled at every pixel center like in Context and written as an image <pattern>.
+func (svg *SVGContext) paint(attr string, c color.Color) string {
+	var def string
+	id := fmt.Sprintf("gradient%d", len(svg.defs)+1)
+	switch g := c.(type) {
+	case *LinearGradient:
+		def = fmt.Sprintf(`<linearGradient id="%s" gradientUnits="userSpaceOnUse" x1="%s" y1="%s" x2="%s" y2="%s"%s>%s</linearGradient>`,
+			id, svgNumber(g.Start.X), svgNumber(g.Start.Y), svgNumber(g.End.X), svgNumber(g.End.Y),
+			svg.gradientAttrs(&g.Gradient), svgStops(&g.Gradient))
+	case *RadialGradient:
+		def = fmt.Sprintf(`<radialGradient id="%s" gradientUnits="userSpaceOnUse" cx="%s" cy="%s" r="%s" fx="%s" fy="%s"%s>%s</radialGradient>`,
+			id, svgNumber(g.Center.X), svgNumber(g.Center.Y), svgNumber(g.Radius), svgNumber(g.Focus.X), svgNumber(g.Focus.Y),
+			svg.gradientAttrs(&g.Gradient), svgStops(&g.Gradient))
+	case Pattern:
+		id = fmt.Sprintf("pattern%d", len(svg.defs)+1)
+		colorAt := pixelSampler(g, svg.state.transform)
+		img := image.NewNRGBA(image.Rect(0, 0, svg.width, svg.height))
+		for y := range svg.height {
+			for x := range svg.width {
+				img.Set(x, y, colorAt(x, y))
+			}
+		}
+		def = fmt.Sprintf(`<pattern id="%s" patternUnits="userSpaceOnUse" width="%d" height="%d"><image width="%d" height="%d" href="%s"/></pattern>`,
+			id, svg.width, svg.height, svg.width, svg.height, svgImageData(img))
+	default:
+		return svgColor(attr, attr+"-opacity", c)
+	}
+	svg.defs = append(svg.defs, def)
+	return fmt.Sprintf(`%s="url(#%s)"`, attr, id)
+}
+
+// svgImageData returns img as a PNG data URI
+func svgImageData(img image.Image) string {
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// svgColor returns the color attribute and the opacity attribute if the color is translucent.
+func svgColor(attr, opacityAttr string, c color.Color) string {
+	if c == nil {
+		return attr + `="none"`
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	s := fmt.Sprintf(`%s="#%02x%02x%02x"`, attr, n.R, n.G, n.B)
+	if n.A != 255 {
+		s += fmt.Sprintf(` %s="%s"`, opacityAttr, svgNumber(float64(n.A)/255))
+	}
+	return s
+}
+
+// gradientAttrs returns spreadMethod and gradientTransform attributes,
+// the gradient is placed with the current transform like the shape it paints
+func (svg *SVGContext) gradientAttrs(g *Gradient) string {
+	s := ""
+	switch g.Spread {
+	case ReflectSpread:
+		s += ` spreadMethod="reflect"`
+	case RepeatSpread:
+		s += ` spreadMethod="repeat"`
+	}
+	if m := svg.state.transform.Mul(g.matrix()); !m.IsIdentity() {
+		s += fmt.Sprintf(` gradientTransform="%s"`, svgMatrix(m))
+	}
+	return s
+}
+
+// svgMatrix formats m as an SVG matrix() transform
+func svgMatrix(m affine.Matrix) string {
+	return fmt.Sprintf("matrix(%s %s %s %s %s %s)",
+		svgNumber(m.A), svgNumber(m.B), svgNumber(m.C), svgNumber(m.D), svgNumber(m.E), svgNumber(m.F))
+}
+
+// svgStops returns <stop> elements of the gradient
+func svgStops(g *Gradient) string {
+	var sb strings.Builder
+	for _, s := range g.Stops {
+		sb.WriteString(`<stop offset="`)
+		sb.WriteString(svgNumber(s.Offset))
+		sb.WriteString(`" `)
+		sb.WriteString(svgColor("stop-color", "stop-opacity", s.Color))
+		sb.WriteString(`/>`)
+	}
+	return sb.String()
+}
+
+// svgNumber formats a coordinate with at most three decimals.
+func svgNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1000)/1000, 'f', -1, 64)
+}
+
+// svgLineCap maps CapMode to stroke-linecap. SVG has no curved caps, so CubicCap and QuadraticCap fall back to round.
+func svgLineCap(c CapMode) string {
+	switch c {
+	case SquareCap:
+		return "square"
+	case RoundCap, CubicCap, QuadraticCap:
+		return "round"
+	}
+	return "butt"
+}
+
+// svgLineJoin maps JoinMode to stroke-linejoin.
+func svgLineJoin(j JoinMode) string {
+	switch j {
+	case RoundJoin:
+		return "round"
+	case BevelJoin:
+		return "bevel"
+	}
+	return "miter"
+}
@@ -0,0 +1,49 @@
+package svg
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// endpointToCenter converts an SVG endpoint arc to center parameterization.
+//
+// It returns the center, the corrected radii and the signed sweep angle. ok is false
+// for degenerate arcs that SVG draws as straight lines. rot is in radians.
+func endpointToCenter(p0 v.Vec, rx, ry, rot float64, large, sweep bool, p1 v.Vec) (center v.Vec, crx, cry, dTheta float64, ok bool) {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 || p0.Equals(p1) {
+		return v.Vec{}, 0, 0, 0, false
+	}
+	sin, cos := math.Sincos(rot)
+	// F.6.5 Conversion from endpoint to center parameterization
+	dx, dy := (p0.X-p1.X)/2, (p0.Y-p1.Y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+	// F.6.6 Correction of out-of-range radii
+	if lambda := (x1*x1)/(rx*rx) + (y1*y1)/(ry*ry); lambda > 1 {
+		s := math.Sqrt(lambda)
+		rx, ry = rx*s, ry*s
+	}
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+	center = v.Vec{
+		X: cos*cx1 - sin*cy1 + (p0.X+p1.X)/2,
+		Y: sin*cx1 + cos*cy1 + (p0.Y+p1.Y)/2,
+	}
+	theta1 := math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	theta2 := math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx)
+	dTheta = theta2 - theta1
+	if sweep && dTheta < 0 {
+		dTheta += 2 * math.Pi
+	} else if !sweep && dTheta > 0 {
+		dTheta -= 2 * math.Pi
+	}
+	return center, rx, ry, dTheta, true
+}
@@ -0,0 +1,37 @@
+package svg_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/setanarut/gog/v2/svg"
+)
+
+// Relative commands with implicit lineto and closepath
+func ExampleParsePath() {
+	paths, _ := svg.ParsePath("m10 10 h20 v20 h-20z M0 0 L5 5")
+	for _, p := range paths {
+		fmt.Println(p.Points, p.IsClosed())
+	}
+	// Output:
+	// [(10.0, 10.0) (30.0, 10.0) (30.0, 30.0) (10.0, 30.0) (10.0, 10.0)] true
+	// [(0.0, 0.0) (5.0, 5.0)] false
+}
+
+// Shape elements with group transforms
+func ExampleDecode() {
+	doc := `<svg xmlns="http://www.w3.org/2000/svg">
+	<defs><rect width="1" height="1"/></defs>
+	<g transform="translate(100 0)">
+		<polyline points="0,0 10,0 10,10"/>
+		<rect x="0" y="0" width="4" height="2" transform="scale(2)"/>
+	</g>
+	</svg>`
+	paths, _ := svg.Decode(strings.NewReader(doc))
+	for _, p := range paths {
+		fmt.Println(p.Points)
+	}
+	// Output:
+	// [(100.0, 0.0) (110.0, 0.0) (110.0, 10.0)]
+	// [(100.0, 0.0) (108.0, 0.0) (108.0, 4.0) (100.0, 4.0) (100.0, 0.0)]
+}
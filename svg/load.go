@@ -0,0 +1,189 @@
+package svg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/setanarut/gog/v2/affine"
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/v"
+)
+
+// Load reads an SVG file and returns the geometry of its shape elements.
+//
+// See Decode for supported elements.
+func Load(filePath string) ([]*path.Path, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f)
+}
+
+// Decode reads an SVG document and returns the geometry of every <path>, <rect>, <circle>,
+// <ellipse>, <polygon> and <polyline> element in document order.
+//
+// Element and group "transform" attributes are applied to the points.
+// Elements inside <defs>, <clipPath>, <mask>, <marker>, <pattern> and <symbol> are skipped.
+// Styling, <use> references and units other than user units are ignored.
+func Decode(r io.Reader) ([]*path.Path, error) {
+	dec := xml.NewDecoder(r)
+	stack := []affine.Matrix{affine.Identity}
+	// depth of non-rendered containers such as <defs>
+	hidden := []bool{false}
+	var paths []*path.Path
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			attrs := make(map[string]string, len(el.Attr))
+			for _, a := range el.Attr {
+				attrs[a.Name.Local] = a.Value
+			}
+			m := stack[len(stack)-1]
+			if t, ok := attrs["transform"]; ok {
+				tm, err := parseTransform(t)
+				if err != nil {
+					return nil, err
+				}
+				m = m.Mul(tm)
+			}
+			stack = append(stack, m)
+			hide := hidden[len(hidden)-1] || nonRendered[el.Name.Local]
+			hidden = append(hidden, hide)
+			if hide {
+				continue
+			}
+			elementPaths, err := elementGeometry(el.Name.Local, attrs)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range elementPaths {
+				p.Transform(m).SetAnchorToCentroid()
+			}
+			paths = append(paths, elementPaths...)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			hidden = hidden[:len(hidden)-1]
+		}
+	}
+	return paths, nil
+}
+
+// nonRendered elements hold geometry that is only drawn by reference
+var nonRendered = map[string]bool{
+	"defs":     true,
+	"clipPath": true,
+	"mask":     true,
+	"marker":   true,
+	"pattern":  true,
+	"symbol":   true,
+}
+
+// elementGeometry returns the untransformed geometry of a shape element.
+func elementGeometry(name string, attrs map[string]string) ([]*path.Path, error) {
+	num := func(key string) float64 {
+		return parseLength(attrs[key])
+	}
+	switch name {
+	case "path":
+		return ParsePath(attrs["d"])
+	case "rect":
+		x, y, w, h := num("x"), num("y"), num("width"), num("height")
+		if w <= 0 || h <= 0 {
+			return nil, nil
+		}
+		rx, okx := attrs["rx"]
+		ry, oky := attrs["ry"]
+		rxv, ryv := parseLength(rx), parseLength(ry)
+		if !okx {
+			rxv = ryv
+		}
+		if !oky {
+			ryv = rxv
+		}
+		rxv, ryv = math.Min(rxv, w/2), math.Min(ryv, h/2)
+		if rxv <= 0 || ryv <= 0 {
+			return ParsePath(fmt.Sprintf("M%g %g h%g v%g h%g Z", x, y, w, h, -w))
+		}
+		return ParsePath(fmt.Sprintf(
+			"M%g %g H%g A%g %g 0 0 1 %g %g V%g A%g %g 0 0 1 %g %g H%g A%g %g 0 0 1 %g %g V%g A%g %g 0 0 1 %g %g Z",
+			x+rxv, y, x+w-rxv,
+			rxv, ryv, x+w, y+ryv, y+h-ryv,
+			rxv, ryv, x+w-rxv, y+h, x+rxv,
+			rxv, ryv, x, y+h-ryv, y+ryv,
+			rxv, ryv, x+rxv, y))
+	case "circle":
+		return ellipse(num("cx"), num("cy"), num("r"), num("r"))
+	case "ellipse":
+		return ellipse(num("cx"), num("cy"), num("rx"), num("ry"))
+	case "polygon", "polyline":
+		pts, err := parsePoints(attrs["points"])
+		if err != nil || len(pts) < 2 {
+			return nil, err
+		}
+		p := path.NewPath(pts)
+		if name == "polygon" {
+			p.Close()
+		}
+		return []*path.Path{p}, nil
+	}
+	return nil, nil
+}
+
+func ellipse(cx, cy, rx, ry float64) ([]*path.Path, error) {
+	if rx <= 0 || ry <= 0 {
+		return nil, nil
+	}
+	return ParsePath(fmt.Sprintf("M%g %g A%g %g 0 1 1 %g %g A%g %g 0 1 1 %g %g Z",
+		cx+rx, cy, rx, ry, cx-rx, cy, rx, ry, cx+rx, cy))
+}
+
+// parsePoints parses the "points" attribute of <polygon> and <polyline>.
+func parsePoints(s string) ([]v.Vec, error) {
+	nums, err := parseNumbers(s)
+	if err != nil {
+		return nil, err
+	}
+	pts := make([]v.Vec, 0, len(nums)/2)
+	for i := 0; i+1 < len(nums); i += 2 {
+		pts = append(pts, v.Vec{X: nums[i], Y: nums[i+1]})
+	}
+	return pts, nil
+}
+
+// parseNumbers parses a list of numbers separated by whitespace and/or commas.
+func parseNumbers(s string) ([]float64, error) {
+	p := &pathParser{s: s}
+	var nums []float64
+	for {
+		p.skipSeparators()
+		if p.pos >= len(p.s) {
+			return nums, nil
+		}
+		n, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, n)
+	}
+}
+
+// parseLength parses a length attribute in user units, "px" suffix is allowed.
+func parseLength(s string) float64 {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "px")
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
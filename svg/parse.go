@@ -0,0 +1,336 @@
+// Package svg reads SVG geometry into path.Path values.
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/v"
+)
+
+// Tolerance is the maximum distance in user units between a flattened curve or arc and the true curve.
+var Tolerance = 0.1
+
+// ParsePath parses SVG path data (the "d" attribute) and returns one Path per subpath.
+//
+// All commands are supported in absolute and relative forms: M L H V C S Q T A Z.
+// Curves and arcs are flattened into line segments with Tolerance.
+// Closed subpaths end with their start point, so IsClosed() reports true.
+func ParsePath(d string) ([]*path.Path, error) {
+	curves, err := ParseCurves(d)
+	if err != nil {
+		return nil, err
+	}
+	return flatten(curves), nil
+}
+
+// ParseCurves parses SVG path data and returns one Curve per subpath, curves and arcs are kept as segments.
+func ParseCurves(d string) ([]*path.Curve, error) {
+	p := &pathParser{s: d}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.curves, nil
+}
+
+// flatten flattens curves with Tolerance
+func flatten(curves []*path.Curve) []*path.Path {
+	paths := make([]*path.Path, len(curves))
+	for i, c := range curves {
+		paths[i] = c.Flatten(Tolerance)
+		paths[i].SetAnchorToCentroid()
+	}
+	return paths
+}
+
+type pathParser struct {
+	s   string
+	pos int
+
+	curves []*path.Curve
+	curve  *path.Curve
+	// current point, subpath start point and last control point
+	cur, start, ctrl v.Vec
+	cmd, lastCmd     byte
+}
+
+func (p *pathParser) parse() error {
+	for {
+		p.skipSeparators()
+		if p.pos >= len(p.s) {
+			break
+		}
+		c := p.s[p.pos]
+		if isCommand(c) {
+			p.cmd = c
+			p.pos++
+		} else if p.cmd == 0 {
+			return fmt.Errorf("svg: expected command at offset %d in path data, found %q", p.pos, c)
+		}
+		// A moveto followed by implicit coordinates continues as lineto
+		if err := p.command(); err != nil {
+			return err
+		}
+		switch p.cmd {
+		case 'M':
+			p.cmd = 'L'
+		case 'm':
+			p.cmd = 'l'
+		}
+	}
+	p.flush(false)
+	return nil
+}
+
+func (p *pathParser) command() error {
+	rel := p.cmd >= 'a'
+	var base v.Vec
+	if rel {
+		base = p.cur
+	}
+	var err error
+	switch p.cmd {
+	case 'M', 'm':
+		var pt v.Vec
+		if pt, err = p.point(base); err != nil {
+			return err
+		}
+		p.flush(false)
+		p.start = pt
+		p.cur = pt
+		p.curve = path.NewCurve(pt)
+	case 'L', 'l':
+		var pt v.Vec
+		if pt, err = p.point(base); err != nil {
+			return err
+		}
+		p.lineTo(pt)
+	case 'H', 'h':
+		var x float64
+		if x, err = p.number(); err != nil {
+			return err
+		}
+		p.lineTo(v.Vec{X: base.X + x, Y: p.cur.Y})
+	case 'V', 'v':
+		var y float64
+		if y, err = p.number(); err != nil {
+			return err
+		}
+		p.lineTo(v.Vec{X: p.cur.X, Y: base.Y + y})
+	case 'C', 'c':
+		var c1, c2, pt v.Vec
+		if c1, err = p.point(base); err != nil {
+			return err
+		}
+		if c2, err = p.point(base); err != nil {
+			return err
+		}
+		if pt, err = p.point(base); err != nil {
+			return err
+		}
+		p.cubicTo(c1, c2, pt)
+	case 'S', 's':
+		var c2, pt v.Vec
+		if c2, err = p.point(base); err != nil {
+			return err
+		}
+		if pt, err = p.point(base); err != nil {
+			return err
+		}
+		c1 := p.cur
+		if isOneOf(p.lastCmd, "CcSs") {
+			c1 = p.cur.Add(p.cur.Sub(p.ctrl))
+		}
+		p.cubicTo(c1, c2, pt)
+	case 'Q', 'q':
+		var c, pt v.Vec
+		if c, err = p.point(base); err != nil {
+			return err
+		}
+		if pt, err = p.point(base); err != nil {
+			return err
+		}
+		p.quadTo(c, pt)
+	case 'T', 't':
+		var pt v.Vec
+		if pt, err = p.point(base); err != nil {
+			return err
+		}
+		c := p.cur
+		if isOneOf(p.lastCmd, "QqTt") {
+			c = p.cur.Add(p.cur.Sub(p.ctrl))
+		}
+		p.quadTo(c, pt)
+	case 'A', 'a':
+		var rx, ry, rot float64
+		var large, sweep bool
+		var pt v.Vec
+		if rx, err = p.number(); err != nil {
+			return err
+		}
+		if ry, err = p.number(); err != nil {
+			return err
+		}
+		if rot, err = p.number(); err != nil {
+			return err
+		}
+		if large, err = p.flag(); err != nil {
+			return err
+		}
+		if sweep, err = p.flag(); err != nil {
+			return err
+		}
+		if pt, err = p.point(base); err != nil {
+			return err
+		}
+		p.arcTo(rx, ry, rot, large, sweep, pt)
+	case 'Z', 'z':
+		p.flush(true)
+		p.cur = p.start
+		// Z takes no arguments, the next token must be a command
+		p.lastCmd = p.cmd
+		p.cmd = 0
+		return nil
+	}
+	p.lastCmd = p.cmd
+	return nil
+}
+
+// flush finishes the current subpath.
+func (p *pathParser) flush(closePath bool) {
+	if p.curve != nil && len(p.curve.Segments) > 0 {
+		if closePath {
+			p.curve.Close()
+		}
+		p.curves = append(p.curves, p.curve.SetAnchorToCentroid())
+	}
+	p.curve = nil
+}
+
+func (p *pathParser) lineTo(pt v.Vec) {
+	p.ensureStarted().LineTo(pt)
+	p.cur = pt
+}
+
+func (p *pathParser) quadTo(c, pt v.Vec) {
+	p.ensureStarted().QuadTo(c, pt)
+	p.ctrl = c
+	p.cur = pt
+}
+
+func (p *pathParser) cubicTo(c1, c2, pt v.Vec) {
+	p.ensureStarted().CubeTo(c1, c2, pt)
+	p.ctrl = c2
+	p.cur = pt
+}
+
+func (p *pathParser) arcTo(rx, ry, rot float64, large, sweep bool, pt v.Vec) {
+	curve := p.ensureStarted()
+	center, rx, ry, dTheta, ok := endpointToCenter(p.cur, rx, ry, rot*math.Pi/180, large, sweep, pt)
+	if ok {
+		curve.ArcTo(center, rx, ry, rot*math.Pi/180, dTheta)
+		// Replace the computed end point with the exact one to avoid drift in relative commands
+		curve.Segments[len(curve.Segments)-1].End = pt
+	} else {
+		curve.LineTo(pt)
+	}
+	p.cur = pt
+}
+
+// ensureStarted starts a subpath at the current point if there is none, as after a closepath.
+func (p *pathParser) ensureStarted() *path.Curve {
+	if p.curve == nil {
+		p.curve = path.NewCurve(p.cur)
+	}
+	return p.curve
+}
+
+func (p *pathParser) point(base v.Vec) (v.Vec, error) {
+	x, err := p.number()
+	if err != nil {
+		return v.Vec{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return v.Vec{}, err
+	}
+	return v.Vec{X: base.X + x, Y: base.Y + y}, nil
+}
+
+// number scans a floating point number, numbers may be packed like "1.5.5-2e1".
+func (p *pathParser) number() (float64, error) {
+	p.skipSeparators()
+	start := p.pos
+	if p.pos < len(p.s) && (p.s[p.pos] == '+' || p.s[p.pos] == '-') {
+		p.pos++
+	}
+	digits, dot := false, false
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c >= '0' && c <= '9' {
+			digits = true
+		} else if c == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+		p.pos++
+	}
+	if digits && p.pos < len(p.s) && (p.s[p.pos] == 'e' || p.s[p.pos] == 'E') {
+		e := p.pos + 1
+		if e < len(p.s) && (p.s[e] == '+' || p.s[e] == '-') {
+			e++
+		}
+		if e < len(p.s) && p.s[e] >= '0' && p.s[e] <= '9' {
+			for e < len(p.s) && p.s[e] >= '0' && p.s[e] <= '9' {
+				e++
+			}
+			p.pos = e
+		}
+	}
+	if !digits {
+		return 0, fmt.Errorf("svg: expected number at offset %d in path data", start)
+	}
+	return strconv.ParseFloat(p.s[start:p.pos], 64)
+}
+
+// flag scans an arc flag, flags may be written without separators like "a1 1 0 0110 10".
+func (p *pathParser) flag() (bool, error) {
+	p.skipSeparators()
+	if p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case '0':
+			p.pos++
+			return false, nil
+		case '1':
+			p.pos++
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("svg: expected arc flag at offset %d in path data", p.pos)
+}
+
+func (p *pathParser) skipSeparators() {
+	for p.pos < len(p.s) && isSeparator(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func isSeparator(c byte) bool {
+	return c == ' ' || c == ',' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isCommand(c byte) bool {
+	return isOneOf(c, "MmLlHhVvCcSsQqTtAaZz")
+}
+
+func isOneOf(c byte, set string) bool {
+	for i := 0; i < len(set); i++ {
+		if set[i] == c {
+			return true
+		}
+	}
+	return false
+}
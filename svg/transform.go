@@ -0,0 +1,73 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/setanarut/gog/v2/affine"
+)
+
+// parseTransform parses the "transform" attribute, e.g. "translate(10 20) rotate(45)".
+func parseTransform(s string) (affine.Matrix, error) {
+	m := affine.Identity
+	for {
+		s = strings.TrimLeft(s, " ,\t\n\r")
+		if s == "" {
+			return m, nil
+		}
+		open := strings.IndexByte(s, '(')
+		end := strings.IndexByte(s, ')')
+		if open < 0 || end < open {
+			return m, fmt.Errorf("svg: invalid transform %q", s)
+		}
+		name := strings.TrimSpace(s[:open])
+		args, err := parseNumbers(s[open+1 : end])
+		if err != nil {
+			return m, err
+		}
+		t, err := transformFunction(name, args)
+		if err != nil {
+			return m, err
+		}
+		m = m.Mul(t)
+		s = s[end+1:]
+	}
+}
+
+func transformFunction(name string, a []float64) (affine.Matrix, error) {
+	arg := func(i int, def float64) float64 {
+		if i < len(a) {
+			return a[i]
+		}
+		return def
+	}
+	switch name {
+	case "matrix":
+		if len(a) == 6 {
+			return affine.Matrix{A: a[0], B: a[1], C: a[2], D: a[3], E: a[4], F: a[5]}, nil
+		}
+	case "translate":
+		if len(a) > 0 {
+			return affine.Translation(a[0], arg(1, 0)), nil
+		}
+	case "scale":
+		if len(a) > 0 {
+			return affine.Scaling(a[0], arg(1, a[0])), nil
+		}
+	case "rotate":
+		if len(a) > 0 {
+			cx, cy := arg(1, 0), arg(2, 0)
+			return affine.Translation(cx, cy).Rotate(a[0]*math.Pi/180).Translate(-cx, -cy), nil
+		}
+	case "skewX":
+		if len(a) == 1 {
+			return affine.Shearing(a[0]*math.Pi/180, 0), nil
+		}
+	case "skewY":
+		if len(a) == 1 {
+			return affine.Shearing(0, a[0]*math.Pi/180), nil
+		}
+	}
+	return affine.Identity, fmt.Errorf("svg: invalid transform function %s%v", name, a)
+}
@@ -0,0 +1,23 @@
+package gog
+
+import (
+	"image/color"
+
+	"github.com/setanarut/gog/v2/font"
+	"github.com/setanarut/v"
+)
+
+// FillText draws s with face at pos, lines are separated by '\n'.
+//
+// pos is the alignment point of the face, the current transform and clip are applied.
+// Use face.Text to get the outline as a shape for animation or path effects.
+func (ctx *Context) FillText(face *font.Face, s string, pos v.Vec, fillColor color.Color) {
+	ctx.Fill(face.Text(s).Translate(pos.X, pos.Y), fillColor)
+}
+
+// StrokeText draws the outline of s with face at pos, lines are separated by '\n'.
+//
+// pos is the alignment point of the face, the current transform and clip are applied.
+func (ctx *Context) StrokeText(face *font.Face, s string, pos v.Vec, strokeStyle *StrokeStyle) {
+	ctx.Stroke(face.Text(s).Translate(pos.X, pos.Y), strokeStyle)
+}
@@ -3,8 +3,8 @@ package utils
 import (
 	"bufio"
 	"image"
+	"image/color"
 	"image/png"
-	"log"
 	"math"
 	"os"
 
@@ -57,26 +57,33 @@ func Linspace(min, max float64, n int) []float64 {
 	return res
 }
 
-// WritePNG writes PNG mage to disk.
-func WritePNG(filePath string, img image.Image) {
+// LerpColor interpolates premultiplied colors, t outside of 0-1 is clamped
+func LerpColor(c0, c1 color.Color, t float64) color.Color {
+	t = min(1, max(0, t))
+	r0, g0, b0, a0 := c0.RGBA()
+	r1, g1, b1, a1 := c1.RGBA()
+	lerp := func(x, y uint32) uint16 {
+		return uint16(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA64{lerp(r0, r1), lerp(g0, g1), lerp(b0, b1), lerp(a0, a1)}
+}
+
+// WritePNG writes PNG image to disk.
+func WritePNG(filePath string, img image.Image) error {
 	outFile, err := os.Create(filePath)
 	if err != nil {
-		log.Println(err)
-		os.Exit(1)
+		return err
 	}
-	defer outFile.Close()
 	b := bufio.NewWriter(outFile)
-	err = png.Encode(b, img)
-	if err != nil {
-		log.Println(err)
-		os.Exit(1)
+	if err = png.Encode(b, img); err != nil {
+		outFile.Close()
+		return err
 	}
-	err = b.Flush()
-	if err != nil {
-		log.Println(err)
-		os.Exit(1)
+	if err = b.Flush(); err != nil {
+		outFile.Close()
+		return err
 	}
-
+	return outFile.Close()
 }
 
 func CloneRGBAImage(img *image.RGBA) image.Image {
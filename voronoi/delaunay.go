@@ -0,0 +1,314 @@
+// Package voronoi computes Delaunay triangulations and Voronoi diagrams of point sets.
+//
+// Triangles, cells and edges are returned as path.Path values, so they can be drawn, offset
+// or combined like any other shape.
+//
+//	d := voronoi.NewDiagram(voronoi.Relax(points, bounds, 3), bounds)
+//	for _, cell := range d.Cells {
+//		if cell.Path != nil {
+//			ctx.Fill(cell.Path.Offset(-2, path.RoundJoin, 0), color.White)
+//		}
+//	}
+package voronoi
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/v"
+)
+
+// Triangulation is a Delaunay triangulation, no point lies inside the circumcircle of a triangle
+type Triangulation struct {
+	Points []v.Vec
+	// Triangles holds indices of Points, turning from +X towards +Y like shapes.Rect
+	Triangles [][3]int
+}
+
+// Triangulate returns the Delaunay triangulation of points with the Bowyer–Watson algorithm.
+//
+// Duplicate points are inserted once, later copies are not part of any triangle.
+// Fewer than 3 points, or points on a line, give no triangles.
+func Triangulate(points []v.Vec) *Triangulation {
+	t, _ := triangulate(points)
+	return t
+}
+
+// Edges returns the edges of all triangles once, as index pairs with the smaller index first
+func (t *Triangulation) Edges() [][2]int {
+	var edges [][2]int
+	for _, tri := range t.Triangles {
+		for k := range 3 {
+			a, b := tri[k], tri[(k+1)%3]
+			edges = append(edges, [2]int{min(a, b), max(a, b)})
+		}
+	}
+	slices.SortFunc(edges, func(x, y [2]int) int {
+		if c := cmp.Compare(x[0], y[0]); c != 0 {
+			return c
+		}
+		return cmp.Compare(x[1], y[1])
+	})
+	return slices.Compact(edges)
+}
+
+// Neighbors returns the indices of the points connected to each point by an edge, in ascending order
+func (t *Triangulation) Neighbors() [][]int {
+	neighbors := make([][]int, len(t.Points))
+	for _, e := range t.Edges() {
+		neighbors[e[0]] = append(neighbors[e[0]], e[1])
+		neighbors[e[1]] = append(neighbors[e[1]], e[0])
+	}
+	for _, n := range neighbors {
+		slices.Sort(n)
+	}
+	return neighbors
+}
+
+// Paths returns the triangles as closed paths
+func (t *Triangulation) Paths() []*path.Path {
+	paths := make([]*path.Path, len(t.Triangles))
+	for i, tri := range t.Triangles {
+		a, b, c := t.Points[tri[0]], t.Points[tri[1]], t.Points[tri[2]]
+		paths[i] = path.NewPath([]v.Vec{a, b, c, a})
+	}
+	return paths
+}
+
+// EdgePaths returns the edges as line paths, in the order of Edges
+func (t *Triangulation) EdgePaths() []*path.Path {
+	edges := t.Edges()
+	paths := make([]*path.Path, len(edges))
+	for i, e := range edges {
+		paths[i] = path.NewPath([]v.Vec{t.Points[e[0]], t.Points[e[1]]})
+	}
+	return paths
+}
+
+// triangle is a triangle of the triangulation being built.
+// Edge k runs from v[k] to v[k+1], adj[k] is the triangle on its other side or -1.
+type triangle struct {
+	v    [3]int
+	adj  [3]int
+	dead bool
+}
+
+// triangulate returns the triangulation and, for each point, the points it shares a triangle
+// with, including the triangles that touch the super triangle
+func triangulate(points []v.Vec) (*Triangulation, [][]int) {
+	t := &Triangulation{Points: points}
+	linked := make([][]int, len(points))
+	if len(points) < 3 {
+		for i := range points {
+			for j := range points {
+				if i != j && points[i] != points[j] {
+					linked[i] = append(linked[i], j)
+				}
+			}
+		}
+		return t, linked
+	}
+	n := len(points)
+	lo, hi := (&path.Path{Points: points}).Bounds()
+	size := max(hi.X-lo.X, hi.Y-lo.Y, 1)
+	m := mesh{points: points}
+	tris := []triangle{{v: [3]int{n, n + 1, n + 2}, adj: [3]int{-1, -1, -1}}}
+
+	// Inserting nearby points one after another keeps the walks short
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	cell := size / math.Max(1, math.Sqrt(float64(n)/4))
+	column := func(i int) int { return int((points[i].X - lo.X) / cell) }
+	slices.SortFunc(order, func(i, j int) int {
+		ci, cj := column(i), column(j)
+		if ci != cj {
+			return cmp.Compare(ci, cj)
+		}
+		if ci%2 == 1 {
+			return cmp.Compare(points[j].Y, points[i].Y)
+		}
+		return cmp.Compare(points[i].Y, points[j].Y)
+	})
+
+	// Duplicates are inserted with the index of their first copy
+	firstIndex := make(map[v.Vec]int, n)
+	for i := n - 1; i >= 0; i-- {
+		firstIndex[points[i]] = i
+	}
+	last := 0
+	var bad, stack []int
+	var boundary [][3]int // a, b and the triangle outside of edge a-b
+	isBad := make(map[int]bool)
+	for _, p := range order {
+		pt := points[p]
+		if firstIndex[pt] != p {
+			continue
+		}
+		start := m.locate(tris, last, pt)
+
+		// The cavity is the connected set of triangles whose circumcircle holds the point
+		bad, stack, boundary = bad[:0], append(stack[:0], start), boundary[:0]
+		clear(isBad)
+		isBad[start] = true
+		for len(stack) > 0 {
+			ti := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			bad = append(bad, ti)
+			for _, nb := range tris[ti].adj {
+				if nb >= 0 && !isBad[nb] && m.inCircle(tris[nb].v, pt) {
+					isBad[nb] = true
+					stack = append(stack, nb)
+				}
+			}
+		}
+		for _, ti := range bad {
+			tr := &tris[ti]
+			tr.dead = true
+			for k, nb := range tr.adj {
+				if nb < 0 || !isBad[nb] {
+					boundary = append(boundary, [3]int{tr.v[k], tr.v[(k+1)%3], nb})
+				}
+			}
+		}
+
+		// Fill the cavity with triangles from its boundary edges to the point
+		first := len(tris)
+		startingAt := make(map[int]int, len(boundary))
+		for _, e := range boundary {
+			ti := len(tris)
+			tris = append(tris, triangle{v: [3]int{e[0], e[1], p}, adj: [3]int{e[2], -1, -1}})
+			startingAt[e[0]] = ti
+			if nb := e[2]; nb >= 0 {
+				for k := range 3 {
+					if tris[nb].v[k] == e[1] && tris[nb].v[(k+1)%3] == e[0] {
+						tris[nb].adj[k] = ti
+					}
+				}
+			}
+		}
+		for ti := first; ti < len(tris); ti++ {
+			b := tris[ti].v[1]
+			next := startingAt[b]
+			tris[ti].adj[1] = next
+			tris[next].adj[2] = ti
+		}
+		last = first
+	}
+
+	for _, tr := range tris {
+		if tr.dead {
+			continue
+		}
+		for k := range 3 {
+			a, b := tr.v[k], tr.v[(k+1)%3]
+			if a < n && b < n {
+				linked[a] = append(linked[a], b)
+			}
+		}
+		if tr.v[0] < n && tr.v[1] < n && tr.v[2] < n {
+			t.Triangles = append(t.Triangles, tr.v)
+		}
+	}
+	for i, l := range linked {
+		slices.Sort(l)
+		linked[i] = slices.Compact(l)
+	}
+	return t, linked
+}
+
+// mesh tests points against triangles whose vertices may be super vertices.
+// Super vertex n+k lies infinitely far away in the direction superDirs[k], so the
+// circumcircles of triangles that touch them become half-planes and the hull comes out convex.
+type mesh struct {
+	points []v.Vec
+}
+
+// superDirs are the directions of the super vertices, turning from +X towards +Y
+var superDirs = [3]v.Vec{{X: -2, Y: -1}, {X: 2, Y: -1}, {X: 0, Y: 2}}
+
+func (m *mesh) super(i int) bool {
+	return i >= len(m.points)
+}
+
+// orient returns the sign of the turn from the edge i-j to pt, positive when pt is on the left
+func (m *mesh) orient(i, j int, pt v.Vec) float64 {
+	switch n := len(m.points); {
+	case i < n && j < n:
+		a := m.points[i]
+		return m.points[j].Sub(a).Cross(pt.Sub(a))
+	case i < n:
+		return superDirs[j-n].Cross(pt.Sub(m.points[i]))
+	case j < n:
+		return superDirs[i-n].Cross(m.points[j].Sub(pt))
+	}
+	// Edges between super vertices have all points on their left
+	return 1
+}
+
+// inCircle reports whether pt lies inside the circumcircle of the triangle
+func (m *mesh) inCircle(tri [3]int, pt v.Vec) bool {
+	supers := 0
+	for _, i := range tri {
+		if m.super(i) {
+			supers++
+		}
+	}
+	// Rotate the super vertices to the end, the orientation does not change
+	for supers == 1 || supers == 2 {
+		if !m.super(tri[0]) && m.super(tri[2]) {
+			break
+		}
+		tri = [3]int{tri[1], tri[2], tri[0]}
+	}
+	switch supers {
+	case 0:
+		a, b, c := m.points[tri[0]].Sub(pt), m.points[tri[1]].Sub(pt), m.points[tri[2]].Sub(pt)
+		return a.MagSq()*b.Cross(c)+b.MagSq()*c.Cross(a)+c.MagSq()*a.Cross(b) > 0
+	case 1:
+		// The half-plane left of the edge, and the inside of the edge itself
+		a, b := m.points[tri[0]], m.points[tri[1]]
+		side := b.Sub(a).Cross(pt.Sub(a))
+		return side > 0 || side == 0 && pt.Sub(a).Dot(pt.Sub(b)) < 0
+	case 2:
+		// The half-plane through the real vertex that faces the far circumcenter
+		center := circumcenter(superDirs[tri[1]-len(m.points)], superDirs[tri[2]-len(m.points)])
+		return pt.Sub(m.points[tri[0]]).Dot(center) > 0
+	}
+	return true
+}
+
+// circumcenter returns the center of the circle through the origin, a and b
+func circumcenter(a, b v.Vec) v.Vec {
+	d := 2 * a.Cross(b)
+	la, lb := a.MagSq(), b.MagSq()
+	return v.Vec{X: b.Y*la - a.Y*lb, Y: a.X*lb - b.X*la}.DivS(d)
+}
+
+// locate returns a live triangle that contains pt, walking from the triangle start
+func (m *mesh) locate(tris []triangle, start int, pt v.Vec) int {
+	ti := start
+	for range len(tris) {
+		tr := tris[ti]
+		moved := false
+		for k := range 3 {
+			if tr.adj[k] >= 0 && m.orient(tr.v[k], tr.v[(k+1)%3], pt) < 0 {
+				ti, moved = tr.adj[k], true
+				break
+			}
+		}
+		if !moved {
+			return ti
+		}
+	}
+	// The walk should not cycle, scan all triangles if it does
+	for i, tr := range tris {
+		if !tr.dead && m.inCircle(tr.v, pt) {
+			return i
+		}
+	}
+	return ti
+}
@@ -0,0 +1,47 @@
+package voronoi_test
+
+import (
+	"fmt"
+
+	"github.com/setanarut/gog/v2/shapes"
+	"github.com/setanarut/gog/v2/voronoi"
+	"github.com/setanarut/v"
+)
+
+// Triangulates four points and splits a square into their cells
+func ExampleNewDiagram() {
+	points := []v.Vec{{X: 10, Y: 10}, {X: 30, Y: 10}, {X: 10, Y: 30}, {X: 32, Y: 32}}
+	d := voronoi.NewDiagram(points, shapes.Square(v.Vec{X: 0, Y: 0}, 40))
+	fmt.Println(d.Triangulation.Triangles)
+	for _, cell := range d.Cells {
+		fmt.Printf("%v %.1f %v\n", cell.Site, cell.Path.Area(), cell.Neighbors)
+	}
+	fmt.Println(len(d.Edges))
+	// Output:
+	// [[0 1 2] [2 1 3]]
+	// (10.0, 10.0) 400.0 [1 2]
+	// (30.0, 10.0) 420.0 [0 2 3]
+	// (10.0, 30.0) 420.0 [0 1 3]
+	// (32.0, 32.0) 360.0 [1 2]
+	// 5
+}
+
+// Spreads clustered points evenly over a square
+func ExampleRelax() {
+	points := []v.Vec{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}}
+	fmt.Println(voronoi.Relax(points, shapes.Square(v.Vec{X: 0, Y: 0}, 40), 50))
+	// Output:
+	// [(10.0, 10.0) (30.0, 10.0) (10.0, 30.0) (30.0, 30.0)]
+}
+
+// Points on a line without bounds are clipped to a padded box
+func ExampleNewDiagram_collinear() {
+	points := []v.Vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 20, Y: 0}}
+	for _, cell := range voronoi.NewDiagram(points, nil).Cells {
+		fmt.Println(cell.Path.Points, cell.Neighbors)
+	}
+	// Output:
+	// [(-10.0, -10.0) (5.0, -10.0) (5.0, 10.0) (-10.0, 10.0) (-10.0, -10.0)] [1]
+	// [(5.0, -10.0) (15.0, -10.0) (15.0, 10.0) (5.0, 10.0) (5.0, -10.0)] [0 2]
+	// [(15.0, -10.0) (30.0, -10.0) (30.0, 10.0) (15.0, 10.0) (15.0, -10.0)] [1]
+}
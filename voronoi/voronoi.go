@@ -0,0 +1,218 @@
+package voronoi
+
+import (
+	"math"
+	"slices"
+
+	"github.com/setanarut/gog/v2/path"
+	"github.com/setanarut/v"
+)
+
+// Cell is the part of the plane closer to its site than to any other point
+type Cell struct {
+	// Site is the point of the cell
+	Site v.Vec
+	// Path is the closed outline of the cell turning from +X towards +Y,
+	// nil when the cell lies outside the bounds or its site is a duplicate
+	Path *path.Path
+	// Neighbors holds the indices of the cells that share an edge with the cell, in ascending order
+	Neighbors []int
+}
+
+// Edge is a boundary between two cells
+type Edge struct {
+	// Indices of the cells on each side, A < B
+	A, B int
+	Path *path.Path
+}
+
+// Diagram is a Voronoi diagram clipped to bounds
+type Diagram struct {
+	// Cells holds the cell of each point, in the order of the points
+	Cells []Cell
+	// Edges holds every edge shared by two cells once. Edges on the bounds are not included.
+	Edges []Edge
+	// Triangulation is the Delaunay triangulation of the points, the dual of the diagram
+	Triangulation *Triangulation
+}
+
+// NewDiagram returns the Voronoi diagram of points clipped to bounds.
+//
+// bounds must be a closed Path, nil uses the bounding box of the points. Points on a line have
+// a box without area, it is padded by half of its length on each side.
+// When bounds is not convex, a cell split into several parts keeps the part that holds its site.
+func NewDiagram(points []v.Vec, bounds *path.Path) *Diagram {
+	t, linked := triangulate(points)
+	d := &Diagram{Cells: make([]Cell, len(points)), Triangulation: t}
+	if len(points) == 0 {
+		return d
+	}
+	frame, convex := clipFrame(points, bounds)
+	lo, hi := (&path.Path{Points: frame}).Bounds()
+	eps := 1e-9 * max(hi.X-lo.X, hi.Y-lo.Y, 1)
+
+	seen := make(map[v.Vec]bool, len(points))
+	for i, site := range points {
+		d.Cells[i].Site = site
+		if seen[site] {
+			continue
+		}
+		seen[site] = true
+		ring := frame
+		for _, j := range linked[i] {
+			ring = clipHalfPlane(ring, site, points[j])
+		}
+		if len(ring) < 3 {
+			continue
+		}
+		cellPath := path.NewPath(append(ring[:len(ring):len(ring)], ring[0]))
+		if !convex {
+			cellPath = largestPart(path.Intersection(cellPath, bounds), site)
+		}
+		d.Cells[i].Path = cellPath
+	}
+
+	// Cell edges that lie on the bisector of two sites are shared by their cells
+	for i, cell := range d.Cells {
+		if cell.Path == nil {
+			continue
+		}
+		pts := cell.Path.Points
+		for k := range len(pts) - 1 {
+			a, b := pts[k], pts[k+1]
+			for _, j := range linked[i] {
+				if j < i || d.Cells[j].Path == nil || a.Dist(b) < eps {
+					continue
+				}
+				if onBisector(a, cell.Site, points[j], eps) && onBisector(b, cell.Site, points[j], eps) {
+					d.Edges = append(d.Edges, Edge{A: i, B: j, Path: path.NewPath([]v.Vec{a, b})})
+					break
+				}
+			}
+		}
+	}
+	for _, e := range d.Edges {
+		if !slices.Contains(d.Cells[e.A].Neighbors, e.B) {
+			d.Cells[e.A].Neighbors = append(d.Cells[e.A].Neighbors, e.B)
+			d.Cells[e.B].Neighbors = append(d.Cells[e.B].Neighbors, e.A)
+		}
+	}
+	for i := range d.Cells {
+		slices.Sort(d.Cells[i].Neighbors)
+	}
+	return d
+}
+
+// Paths returns the paths of all cells, cells without a Path are skipped
+func (d *Diagram) Paths() []*path.Path {
+	var paths []*path.Path
+	for _, c := range d.Cells {
+		if c.Path != nil {
+			paths = append(paths, c.Path)
+		}
+	}
+	return paths
+}
+
+// EdgePaths returns the paths of all edges
+func (d *Diagram) EdgePaths() []*path.Path {
+	paths := make([]*path.Path, len(d.Edges))
+	for i, e := range d.Edges {
+		paths[i] = e.Path
+	}
+	return paths
+}
+
+// Relax moves the points to the centroids of their cells iterations times with Lloyd's algorithm,
+// which spreads them evenly. It returns new points, bounds is used like in NewDiagram.
+// When bounds is nil, the bounding box of the original points is kept for all iterations.
+// Points without a cell do not move.
+func Relax(points []v.Vec, bounds *path.Path, iterations int) []v.Vec {
+	pts := append([]v.Vec(nil), points...)
+	if len(pts) == 0 {
+		return pts
+	}
+	if bounds == nil {
+		bounds = path.NewPath(pointBox(pts))
+		bounds.Close()
+	}
+	for range iterations {
+		d := NewDiagram(pts, bounds)
+		for i, c := range d.Cells {
+			if c.Path != nil {
+				pts[i] = c.Path.AreaCentroid()
+			}
+		}
+	}
+	return pts
+}
+
+// clipFrame returns the ring the cells are clipped from, turning from +X towards +Y,
+// and whether it is the convex bounds itself
+func clipFrame(points []v.Vec, bounds *path.Path) ([]v.Vec, bool) {
+	if bounds == nil {
+		return pointBox(points), true
+	}
+	hull := bounds.ConvexHull()
+	if math.Abs(hull.Area()-bounds.Area()) <= 1e-9*hull.Area() {
+		return hull.Points[:max(hull.Len()-1, 0)], true
+	}
+	return rect(bounds.Bounds()), false
+}
+
+// pointBox returns the corners of the bounding box of points, boxes without area are padded
+func pointBox(points []v.Vec) []v.Vec {
+	lo, hi := (&path.Path{Points: points}).Bounds()
+	if size := hi.Sub(lo); size.X == 0 || size.Y == 0 {
+		pad := max(size.X, size.Y, 1) / 2
+		lo, hi = lo.Sub(v.Vec{X: pad, Y: pad}), hi.Add(v.Vec{X: pad, Y: pad})
+	}
+	return rect(lo, hi)
+}
+
+// rect returns the corners of a box turning from +X towards +Y
+func rect(lo, hi v.Vec) []v.Vec {
+	return []v.Vec{lo, {X: hi.X, Y: lo.Y}, hi, {X: lo.X, Y: hi.Y}}
+}
+
+// clipHalfPlane returns the part of the convex ring that is closer to site than to other
+// with the Sutherland–Hodgman algorithm
+func clipHalfPlane(ring []v.Vec, site, other v.Vec) []v.Vec {
+	if site == other {
+		return ring
+	}
+	normal := other.Sub(site)
+	mid := site.Lerp(other, 0.5)
+	side := func(pt v.Vec) float64 { return pt.Sub(mid).Dot(normal) }
+	var out []v.Vec
+	for k, a := range ring {
+		b := ring[(k+1)%len(ring)]
+		sa, sb := side(a), side(b)
+		if sa <= 0 {
+			out = append(out, a)
+		}
+		if sa < 0 && sb > 0 || sa > 0 && sb < 0 {
+			out = append(out, a.Lerp(b, sa/(sa-sb)))
+		}
+	}
+	return out
+}
+
+// largestPart returns the subpath of c that holds site, or the largest one
+func largestPart(c *path.Compound, site v.Vec) *path.Path {
+	var best *path.Path
+	for _, p := range c.Paths {
+		if p.Contains(site, path.NonZero) {
+			return p
+		}
+		if best == nil || p.Area() > best.Area() {
+			best = p
+		}
+	}
+	return best
+}
+
+// onBisector reports whether pt is equally far from a and b
+func onBisector(pt, a, b v.Vec, eps float64) bool {
+	return math.Abs(pt.Dist(a)-pt.Dist(b)) <= eps
+}